@@ -1,11 +1,23 @@
 package minimu9
 
+import (
+	"fmt"
+)
+
 // DataAvailabilityError is a warning which tells that some data was
 // either lost (not read by the user before it was overwritten with a new value),
 // or not available yet (the measurement frequency is too low).
 type DataAvailabilityError struct {
 	NewDataNotAvailable   bool
 	NewDataWasOverwritten bool
+	// SamplesLost is an estimated number of measurements lost when NewDataWasOverwritten,
+	// if known, or 0 otherwise.
+	SamplesLost int
+	// XNotAvailable, YNotAvailable and ZNotAvailable tell which axes had no new measurement,
+	// and XOverwritten, YOverwritten and ZOverwritten tell which axes had a measurement
+	// overwritten, when decoded from a status byte by StatusError().
+	XNotAvailable, YNotAvailable, ZNotAvailable bool
+	XOverwritten, YOverwritten, ZOverwritten    bool
 }
 
 // Error returns human-readable description string for the error.
@@ -13,8 +25,64 @@ func (e *DataAvailabilityError) Error() string {
 	if e.NewDataNotAvailable {
 		return "Warning: there was no new measurement since the previous read."
 	}
+	if e.NewDataWasOverwritten && e.SamplesLost > 0 {
+		return fmt.Sprintf("Warning: about %d measurements were acquired and lost before "+
+			"the previous was read.", e.SamplesLost)
+	}
 	if e.NewDataWasOverwritten {
 		return "Warning: a new measurement was acquired before the previous was read."
 	}
 	return "An unknown error has occured. Data may be stale."
 }
+
+// SaturationError tells that a measurement on some axes has reached the limit of the sensor
+// range, i.e. the actual value may be beyond it, and a higher full scale is needed.
+type SaturationError struct {
+	X, Y, Z bool
+}
+
+// Error returns human-readable description string for the error.
+func (e *SaturationError) Error() string {
+	axes := ""
+	for _, axis := range []struct {
+		name      string
+		saturated bool
+	}{{"X", e.X}, {"Y", e.Y}, {"Z", e.Z}} {
+		if axis.saturated {
+			axes += axis.name
+		}
+	}
+	return "Measurement has reached the full scale limit on axes: " + axes + "."
+}
+
+// StatusError interprets a status byte, where the upper 4 bits are "overrun" flags and the lower
+// 4 bits are "new data available" flags (X, Y, Z and all axes, from bit 0 to 3 and 4 to 7
+// respectively), into a DataAvailabilityError warning, or nil. Overrun on any axis takes
+// precedence, then no new data on all axes. New data on some axes only is not reported, since
+// it's normal when other axes are disabled. Per-axis flags of the warning are always filled in.
+func StatusError(status byte) error {
+	if status&0xf0 == 0 && status&0x0f != 0 {
+		return nil
+	}
+	return &DataAvailabilityError{
+		NewDataWasOverwritten: status&0xf0 != 0,
+		NewDataNotAvailable:   status&0xf0 == 0,
+		XNotAvailable:         status&(1<<0) == 0,
+		YNotAvailable:         status&(1<<1) == 0,
+		ZNotAvailable:         status&(1<<2) == 0,
+		XOverwritten:          status&(1<<4) != 0,
+		YOverwritten:          status&(1<<5) != 0,
+		ZOverwritten:          status&(1<<6) != 0,
+	}
+}
+
+// ShortReadError tells that the bus has delivered fewer bytes than requested, e.g. because of
+// clock stretching issues or a flaky multiplexer.
+type ShortReadError struct {
+	Expected, Read int
+}
+
+// Error returns human-readable description string for the error.
+func (e *ShortReadError) Error() string {
+	return fmt.Sprintf("Short read from the bus: expected %d bytes, got %d.", e.Expected, e.Read)
+}
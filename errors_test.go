@@ -0,0 +1,63 @@
+package minimu9
+
+import (
+	"testing"
+)
+
+func TestStatusError(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		status byte
+		// expected is nil if no warning is expected.
+		expected *DataAvailabilityError
+	}{
+		{"new data on all axes", 0x0f, nil},
+		{"new data on X only", 0x01, nil},
+		{"new data on X and Y", 0x03, nil},
+		{"ZYXDA only", 0x08, nil},
+		{"no new data", 0x00, &DataAvailabilityError{
+			NewDataNotAvailable: true,
+			XNotAvailable:       true, YNotAvailable: true, ZNotAvailable: true,
+		}},
+		{"overrun on all axes", 0xff, &DataAvailabilityError{
+			NewDataWasOverwritten: true,
+			XOverwritten:          true, YOverwritten: true, ZOverwritten: true,
+		}},
+		{"overrun on X only", 0x1f, &DataAvailabilityError{
+			NewDataWasOverwritten: true,
+			XOverwritten:          true,
+		}},
+		{"overrun without new data", 0xf0, &DataAvailabilityError{
+			NewDataWasOverwritten: true,
+			XNotAvailable:         true, YNotAvailable: true, ZNotAvailable: true,
+			XOverwritten: true, YOverwritten: true, ZOverwritten: true,
+		}},
+		{"ZYXOR and ZYXDA only", 0x88, &DataAvailabilityError{
+			NewDataWasOverwritten: true,
+			XNotAvailable:         true, YNotAvailable: true, ZNotAvailable: true,
+		}},
+		{"overrun on Y and Z, new data on Z", 0x64, &DataAvailabilityError{
+			NewDataWasOverwritten: true,
+			XNotAvailable:         true, YNotAvailable: true,
+			YOverwritten: true, ZOverwritten: true,
+		}},
+	} {
+		e := StatusError(test.status)
+		if test.expected == nil {
+			if e != nil {
+				t.Errorf("%s (0x%02X): expected no warning, got %#v", test.name, test.status, e)
+			}
+			continue
+		}
+		dataErr, ok := e.(*DataAvailabilityError)
+		if !ok {
+			t.Errorf("%s (0x%02X): expected *DataAvailabilityError, got %#v",
+				test.name, test.status, e)
+			continue
+		}
+		if *dataErr != *test.expected {
+			t.Errorf("%s (0x%02X): expected %+v, got %+v",
+				test.name, test.status, *test.expected, *dataErr)
+		}
+	}
+}
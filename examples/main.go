@@ -102,7 +102,7 @@ func socketHandler(ws *websocket.Conn) {
 
 				case "G.frequency":
 					v, _ := strconv.ParseFloat(nameAndValue[1], 32)
-					e = g.SetFrequency(v)
+					_, e = g.SetFrequency(v)
 				case "G.full_scale":
 					v, _ := strconv.Atoi(nameAndValue[1])
 					e = g.SetFullScale(float64(v))
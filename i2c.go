@@ -1,10 +1,20 @@
 package minimu9
 
-import "github.com/dasfoo/i2c"
+// Bus is a subset of i2c.Bus methods used by the drivers. It is satisfied by i2c.Bus,
+// and allows to substitute the bus with a fake, e.g. minimu9test.Bus.
+// ReadSliceFromReg and WriteSliceToReg are called with MSB set in the register address, which
+// makes the slave advance the register on every byte. Buses which don't support such burst
+// transfers can be wrapped with NewSingleByteBus().
+type Bus interface {
+	ReadByteFromReg(addr, reg byte) (byte, error)
+	ReadSliceFromReg(addr, reg byte, data []byte) (int, error)
+	WriteByteToReg(addr, reg, value byte) error
+	WriteSliceToReg(addr, reg byte, data []byte) (int, error)
+}
 
 // WriteBitsToReg reads a byte from a specified address, clears the bits set in mask,
 // then sets them to a specified value and writes back.
-func WriteBitsToReg(bus i2c.Bus, address, reg, mask, value byte) error {
+func WriteBitsToReg(bus Bus, address, reg, mask, value byte) error {
 	var (
 		previousValue byte
 		e             error
@@ -14,3 +24,16 @@ func WriteBitsToReg(bus i2c.Bus, address, reg, mask, value byte) error {
 	}
 	return bus.WriteByteToReg(address, reg, (previousValue&^mask)|(value&mask))
 }
+
+// ReadFullSliceFromReg is the same as bus.ReadSliceFromReg, but returns ShortReadError if fewer
+// bytes than len(data) have been read, so that partially filled data is never decoded.
+func ReadFullSliceFromReg(bus Bus, address, reg byte, data []byte) error {
+	n, e := bus.ReadSliceFromReg(address, reg, data)
+	if e != nil {
+		return e
+	}
+	if n < len(data) {
+		return &ShortReadError{Expected: len(data), Read: n}
+	}
+	return nil
+}
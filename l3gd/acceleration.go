@@ -0,0 +1,28 @@
+package l3gd
+
+import (
+	"github.com/golang/geo/r3"
+)
+
+// AngularAcceleration reads angular speed (as ReadTimed() does) and returns its change since the
+// previous call divided by the time passed, in degrees per second squared. The first call returns
+// zero vector, since there's no previous sample yet. If there was no new sample, zero vector is
+// returned along with the warning, and the previous sample is kept.
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (g *Gyro) AngularAcceleration() (r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	v, t, e := g.readTimed()
+	if isDataNotAvailable(e) || e != nil && !isDataAvailabilityError(e) {
+		return r3.Vector{}, e
+	}
+	var acceleration r3.Vector
+	if !g.accelTime.IsZero() {
+		if dt := t.Sub(g.accelTime).Seconds(); dt > 0 {
+			acceleration = v.Sub(g.accelPrevious).Mul(1 / dt)
+		}
+	}
+	g.accelPrevious = v
+	g.accelTime = t
+	return acceleration, e
+}
@@ -0,0 +1,62 @@
+package l3gd
+
+import (
+	"math"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+const (
+	// autoRangeDownRatio is the fraction of the lower range limit that the rate has to stay
+	// within for the automatic range selection to switch down.
+	autoRangeDownRatio = 0.5
+	// autoRangeCalmSamples is how many consecutive samples the rate has to stay within
+	// autoRangeDownRatio of the lower range limit for the automatic range selection to switch down.
+	autoRangeCalmSamples = 50
+)
+
+// EnableAutoRange turns automatic full scale selection in ReadDPS() (and Read()) on or off;
+// it is off by default. When on, the full scale is switched to the next wider range as soon as
+// a sample is saturated (see ReadDPSChecked()) on any axis, and to the next narrower range once
+// the rate has stayed within half of the narrower range limit on all axes for 50 consecutive
+// samples. The gap between the two thresholds and the delay prevent switching back and forth.
+// The saturated sample itself is still returned as measured. Use Sensitivity() to find out
+// the current full scale.
+func (g *Gyro) EnableAutoRange(enable bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.autoRange = enable
+	g.autoRangeCalm = 0
+}
+
+// adjustRange switches the full scale if needed, given a new sample v in degrees per second.
+func (g *Gyro) adjustRange(v r3.Vector) error {
+	peak := math.Max(math.Abs(v.X), math.Max(math.Abs(v.Y), math.Abs(v.Z)))
+	index := int(g.fullScaleIndex)
+	if peak >= saturationCounts*scaleRatio[index] {
+		g.autoRangeCalm = 0
+		if index == len(scaleBits)-1 {
+			return nil
+		}
+		return g.setFullScaleIndex(index + 1)
+	}
+	if index == 0 || peak >= saturationCounts*scaleRatio[index-1]*autoRangeDownRatio {
+		g.autoRangeCalm = 0
+		return nil
+	}
+	if g.autoRangeCalm++; g.autoRangeCalm < autoRangeCalmSamples {
+		return nil
+	}
+	g.autoRangeCalm = 0
+	return g.setFullScaleIndex(index - 1)
+}
+
+func (g *Gyro) setFullScaleIndex(index int) error {
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl4,
+		(1<<4)|(1<<5), byte(index)<<4); e != nil {
+		return e
+	}
+	g.fullScaleIndex = byte(index)
+	return nil
+}
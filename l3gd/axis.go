@@ -0,0 +1,46 @@
+package l3gd
+
+import (
+	"fmt"
+
+	"github.com/dasfoo/minimu9"
+)
+
+// Axis is an axis of angular speed readings.
+type Axis int
+
+const (
+	// AxisX is X axis.
+	AxisX Axis = iota
+	// AxisY is Y axis.
+	AxisY
+	// AxisZ is Z axis.
+	AxisZ
+)
+
+// ReadAxis reads angular speed around a single axis, in degrees per second, same as the
+// corresponding component of Read() would be (including Offset, scale trim, axis mapping and
+// frame). Only the two output registers of the axis are read, which saves bus traffic when only
+// one axis matters. Unlike Read(), it does not check data "freshness". Enable
+// SetBlockDataUpdate() to make sure both bytes come from the same sample.
+func (g *Gyro) ReadAxis(axis Axis) (float64, error) {
+	if axis < AxisX || axis > AxisZ {
+		return 0, fmt.Errorf("unsupported axis %d", axis)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e := g.updateTempBias(); e != nil {
+		return 0, e
+	}
+	sensorAxis := g.axisMap.remap[axis]
+	data := make([]byte, 2)
+	// Set MSB for the slave to advance the register on every read.
+	if e := minimu9.ReadFullSliceFromReg(g.bus, g.address,
+		(regOutX+byte(sensorAxis)*2)|(1<<7), data); e != nil {
+		return 0, e
+	}
+	offset := [3]float64{g.Offset.X, g.Offset.Y, g.Offset.Z}[sensorAxis]
+	trim := [3]float64{g.scaleTrim.X, g.scaleTrim.Y, g.scaleTrim.Z}[sensorAxis]
+	v := float64(int16(g.byteOrder.Uint16(data)))*scaleRatio[g.fullScaleIndex] - offset
+	return frameSigns[g.frame][axis] * g.axisMap.signs[axis] * v * trim, nil
+}
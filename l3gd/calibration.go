@@ -0,0 +1,292 @@
+package l3gd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+// isDataNotAvailable tells whether e is a warning that there was no new measurement to read.
+func isDataNotAvailable(e error) bool {
+	dataErr, ok := e.(*minimu9.DataAvailabilityError)
+	return ok && dataErr.NewDataNotAvailable
+}
+
+// isDataAvailabilityError tells whether e is a minimu9.DataAvailabilityError warning.
+func isDataAvailabilityError(e error) bool {
+	_, ok := e.(*minimu9.DataAvailabilityError)
+	return ok
+}
+
+// freshTimeoutPeriods is for how many data rate periods readFresh waits for a new measurement.
+const freshTimeoutPeriods = 5
+
+// ErrNoNewData is returned by methods waiting for new measurements (e.g. CalibrateBias() or
+// WaitSettle()) when the sensor hasn't produced one in time, because it's in power-down or sleep
+// mode, or all axes are disabled.
+var ErrNoNewData = errors.New("no new measurement in time, the sensor is not measuring")
+
+// readFresh reads angular speed in degrees per second without subtracting Offset,
+// waiting for a new measurement if there was none yet, for up to 5 data rate periods.
+// Warnings about overwritten data are ignored, since the measurement is still fresh.
+func (g *Gyro) readFresh() (r3.Vector, error) {
+	return g.readFreshContext(context.Background())
+}
+
+// readFreshContext is the same as readFresh, but stops waiting when ctx is cancelled.
+func (g *Gyro) readFreshContext(ctx context.Context) (r3.Vector, error) {
+	deadline := time.Now().Add(
+		time.Duration(freshTimeoutPeriods * float64(time.Second) / g.frequency))
+	for {
+		v, e := g.readScaled()
+		if isDataNotAvailable(e) {
+			if time.Now().After(deadline) {
+				return r3.Vector{}, ErrNoNewData
+			}
+			select {
+			case <-ctx.Done():
+				return r3.Vector{}, ctx.Err()
+			case <-time.After(time.Millisecond):
+			}
+			continue
+		}
+		if isDataAvailabilityError(e) {
+			e = nil
+		}
+		return v, e
+	}
+}
+
+// CalibrateBias measures zero-rate bias by averaging the specified number of new samples.
+// The bias is then saved to Offset field (see also SetBias) and returned.
+// NOTE: during calibration, the sensor has to be static (not moving).
+func (g *Gyro) CalibrateBias(samples int) (r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	bias, e := g.average(samples)
+	if e != nil {
+		return r3.Vector{}, e
+	}
+	g.Offset = bias
+	return g.Offset, nil
+}
+
+// CalibrateBiasContext is the same as CalibrateBias(), but stops when ctx is cancelled or its
+// deadline is exceeded, and returns ctx.Err() then. The samples averaged so far are discarded and
+// Offset is left unchanged, since a bias averaged over fewer samples than requested is not
+// accurate enough to be used in place of the previous one.
+func (g *Gyro) CalibrateBiasContext(ctx context.Context, samples int) (r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	bias, e := g.averageContext(ctx, samples)
+	if e != nil {
+		return r3.Vector{}, e
+	}
+	g.Offset = bias
+	return g.Offset, nil
+}
+
+// average returns an average of the specified number of new samples read by readFresh().
+func (g *Gyro) average(samples int) (r3.Vector, error) {
+	return g.averageContext(context.Background(), samples)
+}
+
+// averageContext is the same as average, but stops when ctx is cancelled.
+func (g *Gyro) averageContext(ctx context.Context, samples int) (r3.Vector, error) {
+	if samples <= 0 {
+		return r3.Vector{}, fmt.Errorf("number of samples must be positive, got %d", samples)
+	}
+	var sum r3.Vector
+	for i := 0; i < samples; i++ {
+		if e := ctx.Err(); e != nil {
+			return r3.Vector{}, e
+		}
+		v, e := g.readFreshContext(ctx)
+		if e != nil {
+			return r3.Vector{}, e
+		}
+		sum = sum.Add(v)
+	}
+	return sum.Mul(1 / float64(samples)), nil
+}
+
+// SetBias sets zero-rate bias, in degrees per second, which is subtracted from every reading.
+// Use it to restore a bias previously measured by Calibrate() or CalibrateBias().
+func (g *Gyro) SetBias(bias r3.Vector) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.Offset = bias
+}
+
+// Bias returns zero-rate bias, in degrees per second, which is subtracted from every reading.
+func (g *Gyro) Bias() r3.Vector {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.Offset
+}
+
+// unityScaleTrim is the default scale trim, which doesn't change readings.
+var unityScaleTrim = r3.Vector{X: 1, Y: 1, Z: 1}
+
+// SetScaleTrim sets per-axis sensitivity correction: after subtracting Offset, each axis of every
+// reading is multiplied by the corresponding component of trim, to correct for scale factor
+// errors beyond the nominal sensitivity (e.g. measured by rotating the sensor through a known
+// angle). Like Offset, trim is in the sensor frame (see SetAxisMap()). Defaults to 1 for each
+// axis, which doesn't change readings.
+func (g *Gyro) SetScaleTrim(trim r3.Vector) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.scaleTrim = trim
+}
+
+// ScaleTrim returns per-axis sensitivity correction set by SetScaleTrim().
+func (g *Gyro) ScaleTrim() r3.Vector {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.scaleTrim
+}
+
+// CalibrateBiasRobust is the same as CalibrateBias(), but rejects outliers (e.g. caused by a bump)
+// beyond stddevThreshold standard deviations from the mean on any axis, and averages the rest.
+// It also returns standard deviation of the remaining samples (noise), in degrees per second;
+// a high value means that the sensor probably wasn't static, and the bias is not trustworthy.
+// NOTE: during calibration, the sensor has to be static (not moving).
+func (g *Gyro) CalibrateBiasRobust(samples int, stddevThreshold float64) (
+	bias, noise r3.Vector, e error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if samples <= 0 {
+		return r3.Vector{}, r3.Vector{},
+			fmt.Errorf("number of samples must be positive, got %d", samples)
+	}
+	collected := make([]r3.Vector, samples)
+	for i := range collected {
+		if collected[i], e = g.readFresh(); e != nil {
+			return r3.Vector{}, r3.Vector{}, e
+		}
+	}
+	mean, stddev := meanAndStddev(collected)
+	limit := stddev.Mul(stddevThreshold)
+	inliers := collected[:0]
+	for _, v := range collected {
+		deviation := v.Sub(mean).Abs()
+		if deviation.X <= limit.X && deviation.Y <= limit.Y && deviation.Z <= limit.Z {
+			inliers = append(inliers, v)
+		}
+	}
+	if len(inliers) == 0 {
+		return r3.Vector{}, r3.Vector{},
+			fmt.Errorf("all %d samples are beyond %v standard deviations", samples, stddevThreshold)
+	}
+	bias, noise = meanAndStddev(inliers)
+	g.Offset = bias
+	return bias, noise, nil
+}
+
+// meanAndStddev computes per-axis mean and population standard deviation of samples.
+func meanAndStddev(samples []r3.Vector) (mean, stddev r3.Vector) {
+	for _, v := range samples {
+		mean = mean.Add(v)
+	}
+	mean = mean.Mul(1 / float64(len(samples)))
+	var variance r3.Vector
+	for _, v := range samples {
+		d := v.Sub(mean)
+		variance = variance.Add(r3.Vector{X: d.X * d.X, Y: d.Y * d.Y, Z: d.Z * d.Z})
+	}
+	variance = variance.Mul(1 / float64(len(samples)))
+	return mean, r3.Vector{
+		X: math.Sqrt(variance.X),
+		Y: math.Sqrt(variance.Y),
+		Z: math.Sqrt(variance.Z),
+	}
+}
+
+// MeasureNoise collects the specified number of new samples and returns per-axis RMS noise
+// (deviation from the mean), in degrees per second. Compare it with the datasheet noise density
+// multiplied by the square root of the bandwidth to spot problems like a too wide bandwidth or
+// a noisy power supply.
+// NOTE: during measurement, the sensor has to be static (not moving).
+func (g *Gyro) MeasureNoise(samples int) (r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.measureNoise(samples)
+}
+
+// measureNoise returns standard deviation of the specified number of samples read by readFresh().
+func (g *Gyro) measureNoise(samples int) (r3.Vector, error) {
+	if samples <= 0 {
+		return r3.Vector{}, fmt.Errorf("number of samples must be positive, got %d", samples)
+	}
+	collected := make([]r3.Vector, samples)
+	for i := range collected {
+		var e error
+		if collected[i], e = g.readFresh(); e != nil {
+			return r3.Vector{}, e
+		}
+	}
+	_, noise := meanAndStddev(collected)
+	return noise, nil
+}
+
+// CalibrationData is a persistable set of calibration parameters (e.g. measured once in the lab
+// and shipped with the firmware), which can be serialized to JSON.
+type CalibrationData struct {
+	// Time is when the data was saved.
+	Time time.Time
+	// FullScale is the full scale the calibration was done at, since bias depends on it.
+	FullScale float64
+	// Bias is as returned by Bias().
+	Bias r3.Vector
+	// ScaleTrim is as returned by ScaleTrim().
+	ScaleTrim r3.Vector
+	// TempBias is temperature compensation model as set by SetTempCompensatedBias(), if any.
+	TempBias *TempCompensatedBias `json:",omitempty"`
+}
+
+// SaveCalibration returns the current calibration parameters, to be restored later with
+// LoadCalibration().
+func (g *Gyro) SaveCalibration() CalibrationData {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c := CalibrationData{
+		Time:      time.Now(),
+		FullScale: scaleBits[g.fullScaleIndex],
+		Bias:      g.Offset,
+		ScaleTrim: g.scaleTrim,
+	}
+	if g.tempBias != nil {
+		model := *g.tempBias
+		c.TempBias = &model
+	}
+	return c
+}
+
+// LoadCalibration restores calibration parameters saved by SaveCalibration(). The data is
+// rejected if it has been saved at a full scale different from the current one (see
+// SetFullScale()), because zero-rate bias differs between full scales.
+func (g *Gyro) LoadCalibration(c CalibrationData) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if c.FullScale != scaleBits[g.fullScaleIndex] {
+		return fmt.Errorf("calibration was done at %v degrees/s full scale, but %v is set",
+			c.FullScale, scaleBits[g.fullScaleIndex])
+	}
+	g.Offset = c.Bias
+	g.scaleTrim = c.ScaleTrim
+	if g.scaleTrim == (r3.Vector{}) {
+		// Not set, e.g. in hand-written data.
+		g.scaleTrim = unityScaleTrim
+	}
+	g.tempBias = nil
+	if c.TempBias != nil {
+		model := *c.TempBias
+		g.tempBias = &model
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package l3gd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dasfoo/minimu9"
+)
+
+// Config is a snapshot of the sensor configuration, which can be serialized (e.g. to JSON)
+// to be restored later, or applied to another sensor.
+type Config struct {
+	// Frequency is output data rate, see SetFrequency().
+	Frequency float64
+	// FullScale is full scale, see SetFullScale().
+	FullScale float64
+	// PowerDown tells that the sensor is in power-down mode. Otherwise it's in normal mode,
+	// or in sleep mode if no axes are enabled.
+	PowerDown bool
+	// XEnabled, YEnabled and ZEnabled tell which axes are enabled, see SetAxesEnabled().
+	XEnabled, YEnabled, ZEnabled bool
+	// Bandwidth is low-pass filter bandwidth selection (BW bits), 0..3. The actual cutoff
+	// depends on Frequency, see SetBandwidth().
+	Bandwidth int
+	// BandwidthHz is the actual low-pass filter cutoff, in Hz, see Bandwidth(). It is decoded
+	// from Bandwidth, Frequency and LowPassOutput, and ignored by Apply().
+	BandwidthHz float64
+	// HighPassMode and HighPassCutoff configure high-pass filter, see SetHighPassFilter().
+	HighPassMode   HPFMode
+	HighPassCutoff int
+	// HighPassOutput tells that high-pass filter is enabled, see EnableHighPassFilterOutput().
+	HighPassOutput bool
+	// LowPassOutput tells that the output goes through the low-pass filter set by Bandwidth,
+	// see SetBandwidth(). It is implied by HighPassOutput.
+	LowPassOutput bool
+	// HighPassReference is high-pass filter reference, see SetHighPassReference(). It's only read
+	// in HPFModeReference mode, see Config().
+	HighPassReference int8
+	// BlockDataUpdate tells that BDU is enabled, see SetBlockDataUpdate().
+	BlockDataUpdate bool
+	// BigEndian tells that the output is big endian, see SetBigEndian().
+	BigEndian bool
+	// FIFOEnabled, FIFOMode and FIFOThreshold configure FIFO, see EnableFIFO(), SetFIFOMode()
+	// and SetFIFOThreshold().
+	FIFOEnabled   bool
+	FIFOMode      FIFOMode
+	FIFOThreshold int
+}
+
+// configRegister is a part of a register covered by Config.
+type configRegister struct {
+	reg, mask, value byte
+}
+
+// configRegisters lists configuration registers in the order Apply() writes them.
+// CTRL1 comes last, so that the sensor only starts measuring when fully configured.
+var configRegisters = []byte{
+	regLowOdr, regCtrl2, regCtrl4, regCtrl5, regReference, regFifoCtrl, regCtrl1,
+}
+
+func boolBit(value bool, bit uint) byte {
+	if value {
+		return 1 << bit
+	}
+	return 0
+}
+
+// encode validates the configuration and converts it into parts of configRegisters.
+func (c *Config) encode() ([]configRegister, error) {
+	if !(c.Frequency >= frequencies[0] && c.Frequency <= frequencies[len(frequencies)-1]) {
+		return nil, fmt.Errorf("data rate %v Hz is out of range %v .. %v Hz",
+			c.Frequency, frequencies[0], frequencies[len(frequencies)-1])
+	}
+	frequencyBits := byte(frequencyIndex(c.Frequency))
+	lowOdr := byte(1)
+	if frequencyBits > 2 {
+		frequencyBits -= 3
+		lowOdr = 0
+	}
+	fullScaleIndex := -1
+	for index, scale := range scaleBits {
+		if scale == c.FullScale {
+			fullScaleIndex = index
+		}
+	}
+	if fullScaleIndex < 0 {
+		return nil, fmt.Errorf("unsupported full scale %v degrees/s, must be one of %v",
+			c.FullScale, scaleBits)
+	}
+	if c.Bandwidth < 0 || c.Bandwidth > 3 {
+		return nil, fmt.Errorf("bandwidth selection %d is out of range 0..3", c.Bandwidth)
+	}
+	if c.HighPassMode > HPFModeAutoreset {
+		return nil, fmt.Errorf("unsupported high-pass filter mode %d", c.HighPassMode)
+	}
+	if c.HighPassCutoff < 0 || c.HighPassCutoff >= highPassCutoffs {
+		return nil, fmt.Errorf("high-pass filter cutoff selection %d is out of range 0..%d",
+			c.HighPassCutoff, highPassCutoffs-1)
+	}
+	if c.FIFOMode > FIFOModeBypassToStream {
+		return nil, fmt.Errorf("unsupported FIFO mode %d", c.FIFOMode)
+	}
+	if c.FIFOThreshold < 0 || c.FIFOThreshold >= fifoSize {
+		return nil, fmt.Errorf("FIFO threshold %d is out of range 0..%d",
+			c.FIFOThreshold, fifoSize-1)
+	}
+	return []configRegister{
+		{regLowOdr, 1, lowOdr},
+		{regCtrl2, 0x3f, byte(c.HighPassMode)<<4 | byte(c.HighPassCutoff)},
+		{regCtrl4, 0xf0, boolBit(c.BlockDataUpdate, 7) | boolBit(c.BigEndian, 6) |
+			byte(fullScaleIndex)<<4},
+		{regCtrl5, (1 << 6) | (1 << 4) | 0x03, boolBit(c.FIFOEnabled, 6) |
+			boolBit(c.HighPassOutput, 4) | boolBit(c.HighPassOutput || c.LowPassOutput, 1)},
+		{regReference, 0xff, byte(c.HighPassReference)},
+		{regFifoCtrl, 0xff, byte(c.FIFOMode)<<5 | byte(c.FIFOThreshold)},
+		{regCtrl1, 0xff, frequencyBits<<6 | byte(c.Bandwidth)<<4 | boolBit(!c.PowerDown, 3) |
+			boolBit(c.ZEnabled, 2) | boolBit(c.YEnabled, 1) | boolBit(c.XEnabled, 0)},
+	}, nil
+}
+
+// decodeConfig converts configRegisters values into Config.
+func decodeConfig(registers map[byte]byte) Config {
+	ctrl1, ctrl2, ctrl4, ctrl5 := registers[regCtrl1], registers[regCtrl2],
+		registers[regCtrl4], registers[regCtrl5]
+	fullScaleIndex := int(ctrl4>>4) & 0x03
+	if fullScaleIndex >= len(scaleBits) {
+		// Both 10 and 11 select 2000 degrees/s.
+		fullScaleIndex = len(scaleBits) - 1
+	}
+	return Config{
+		Frequency:         frequencies[decodeFrequencyIndex(ctrl1, registers[regLowOdr])],
+		FullScale:         scaleBits[fullScaleIndex],
+		PowerDown:         ctrl1&(1<<3) == 0,
+		XEnabled:          ctrl1&(1<<0) != 0,
+		YEnabled:          ctrl1&(1<<1) != 0,
+		ZEnabled:          ctrl1&(1<<2) != 0,
+		Bandwidth:         int(ctrl1>>4) & 0x03,
+		BandwidthHz:       decodeBandwidth(ctrl1, registers[regLowOdr], ctrl5),
+		HighPassMode:      HPFMode(ctrl2>>4) & 0x03,
+		HighPassCutoff:    int(ctrl2 & 0x0f),
+		HighPassOutput:    ctrl5&(1<<4) != 0,
+		LowPassOutput:     ctrl5&(1<<1) != 0,
+		HighPassReference: int8(registers[regReference]),
+		BlockDataUpdate:   ctrl4&(1<<7) != 0,
+		BigEndian:         ctrl4&(1<<6) != 0,
+		FIFOEnabled:       ctrl5&(1<<6) != 0,
+		FIFOMode:          FIFOMode(registers[regFifoCtrl] >> 5),
+		FIFOThreshold:     int(registers[regFifoCtrl] & 0x1f),
+	}
+}
+
+// referenceReadable tells whether REFERENCE register is read with CTRL2 set to ctrl2: it's only
+// used in HPFModeReference mode, and reading it resets the filter in HPFModeNormalWithReset mode.
+// CTRL2 comes before REFERENCE in configRegisters, so that it's known by then.
+func referenceReadable(ctrl2 byte) bool {
+	return HPFMode(ctrl2>>4)&0x03 == HPFModeReference
+}
+
+// Config reads the sensor configuration. REFERENCE register is only read in HPFModeReference
+// mode, since reading it resets the high-pass filter in HPFModeNormalWithReset mode (see
+// HighPassReference()); HighPassReference is 0 in other modes.
+func (g *Gyro) Config() (Config, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	registers := make(map[byte]byte)
+	for _, reg := range configRegisters {
+		if reg == regReference && !referenceReadable(registers[regCtrl2]) {
+			continue
+		}
+		value, e := g.bus.ReadByteFromReg(g.address, reg)
+		if e != nil {
+			return Config{}, e
+		}
+		registers[reg] = value
+	}
+	return decodeConfig(registers), nil
+}
+
+// Apply writes the configuration to the sensor, e.g. one obtained with Config().
+// The registers are read before writing, and if a write fails, the written registers (including
+// the failed one) are restored on a best-effort basis, so that the sensor isn't left half
+// configured: all of them are attempted even if some fail. The error of the write is returned,
+// along with the errors of restoring if any (e.g. on a broken bus), listing the registers whose
+// state is unknown then. Like Config(), Apply() does not read REFERENCE register unless in
+// HPFModeReference mode, so it's not restored otherwise.
+func (g *Gyro) Apply(c Config) error {
+	registers, e := c.encode()
+	if e != nil {
+		return e
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	previous := make([]byte, len(registers))
+	unread := make([]bool, len(registers))
+	var ctrl2 byte
+	for i, r := range registers {
+		if r.reg == regReference && !referenceReadable(ctrl2) {
+			unread[i] = true
+			continue
+		}
+		if previous[i], e = g.bus.ReadByteFromReg(g.address, r.reg); e != nil {
+			return e
+		}
+		if r.reg == regCtrl2 {
+			ctrl2 = previous[i]
+		}
+	}
+	for i, r := range registers {
+		if r.mask == 0xff {
+			// Whole register, no need to read it (which can have side effects, e.g. REFERENCE).
+			e = g.bus.WriteByteToReg(g.address, r.reg, r.value)
+		} else {
+			e = minimu9.WriteBitsToReg(g.bus, g.address, r.reg, r.mask, r.value)
+		}
+		if e != nil {
+			// Restore in reverse order, so that CTRL1 (if written) stops measuring first.
+			var restoreErrs []string
+			for ; i >= 0; i-- {
+				if unread[i] {
+					continue
+				}
+				if restoreErr := g.bus.WriteByteToReg(g.address, registers[i].reg,
+					previous[i]); restoreErr != nil {
+					restoreErrs = append(restoreErrs, fmt.Sprintf("register 0x%02X: %v",
+						registers[i].reg, restoreErr))
+				}
+			}
+			if len(restoreErrs) > 0 {
+				return fmt.Errorf("%v; restoring the previous configuration has failed: %s",
+					e, strings.Join(restoreErrs, "; "))
+			}
+			return e
+		}
+	}
+	g.applied = registers
+	g.frequency = frequencies[frequencyIndex(c.Frequency)]
+	for index, scale := range scaleBits {
+		if scale == c.FullScale {
+			g.fullScaleIndex = byte(index)
+		}
+	}
+	g.axesBits = boolBit(c.ZEnabled, 2) | boolBit(c.YEnabled, 1) | boolBit(c.XEnabled, 0)
+	g.byteOrder = binary.LittleEndian
+	if c.BigEndian {
+		g.byteOrder = binary.BigEndian
+	}
+	return nil
+}
+
+// Verify reads back the configuration registers written by the last Apply() and checks that they
+// hold the written values, e.g. to catch writes silently lost on an unreliable bus during
+// initialization. Returns an error listing every mismatch. It is a separate step, so that Apply()
+// doesn't pay for the read-back unless needed; changes made with other methods after Apply()
+// are reported as mismatches too. REFERENCE register is only verified in HPFModeReference mode,
+// see Config().
+func (g *Gyro) Verify() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.applied == nil {
+		return errors.New("no configuration to verify, call Apply() first")
+	}
+	var mismatches []string
+	var ctrl2 byte
+	for _, r := range g.applied {
+		if r.reg == regReference && !referenceReadable(ctrl2) {
+			continue
+		}
+		value, e := g.bus.ReadByteFromReg(g.address, r.reg)
+		if e != nil {
+			return e
+		}
+		if r.reg == regCtrl2 {
+			ctrl2 = value
+		}
+		if value&r.mask != r.value&r.mask {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"register 0x%02X: expected 0x%02X, got 0x%02X (mask 0x%02X)",
+				r.reg, r.value&r.mask, value&r.mask, r.mask))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("configuration mismatch: %s", strings.Join(mismatches, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package l3gd
+
+import (
+	"testing"
+
+	"github.com/dasfoo/minimu9/minimu9test"
+)
+
+// referenceBus is a fake bus which fails the test on reading REFERENCE register, which resets
+// the high-pass filter in HPFModeNormalWithReset mode.
+type referenceBus struct {
+	*minimu9test.Bus
+	t *testing.T
+}
+
+func (b referenceBus) ReadByteFromReg(addr, reg byte) (byte, error) {
+	if reg&0x7f == regReference {
+		b.t.Errorf("unexpected read of REFERENCE register")
+	}
+	return b.Bus.ReadByteFromReg(addr, reg)
+}
+
+func TestConfigDoesNotReadReference(t *testing.T) {
+	bus := newTestBus()
+	bus.SetRegister(testAddress, regReference, 0x12)
+	g := NewGyro(referenceBus{bus, t}, testAddress)
+	c, e := g.Config()
+	if e != nil {
+		t.Fatal(e)
+	}
+	c.Frequency = 100
+	c.FullScale = 245
+	if e = g.Apply(c); e != nil {
+		t.Fatal(e)
+	}
+	if e = g.Verify(); e != nil {
+		t.Error(e)
+	}
+}
+
+func TestConfigReadsReferenceInReferenceMode(t *testing.T) {
+	bus := newTestBus()
+	bus.SetRegister(testAddress, regCtrl2, byte(HPFModeReference)<<4)
+	bus.SetRegister(testAddress, regReference, 0x12)
+	g := NewGyro(bus, testAddress)
+	c, e := g.Config()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if c.HighPassReference != 0x12 {
+		t.Errorf("expected HighPassReference 0x12, got 0x%02X", c.HighPassReference)
+	}
+}
@@ -0,0 +1,71 @@
+package l3gd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+// defaultConsistencyThreshold is the default for SetConsistencyThreshold(), in raw counts:
+// half of the error a torn read causes.
+const defaultConsistencyThreshold = 128
+
+// SetConsistencyThreshold sets by how many raw counts, 1..32767, two reads by ReadConsistent()
+// may differ on any axis and still be considered consistent. A read mixing the high byte of one
+// sample with the low byte of another is off by about 256 counts, so the threshold has to be
+// below that, but above the change between consecutive samples caused by actual rotation;
+// the default is 128 counts.
+func (g *Gyro) SetConsistencyThreshold(counts int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if counts < 1 || counts > maxThreshold {
+		return fmt.Errorf("consistency threshold %d is out of range 1..%d", counts, maxThreshold)
+	}
+	g.consistency = counts
+	return nil
+}
+
+// ReadConsistent reads a single sample, same as Read() without oversampling (see
+// SetOversampling()), but mitigates torn reads (see SetBlockDataUpdate()) in software, without
+// reconfiguring the sensor: it reads twice, and if the reads differ by more than the threshold
+// set with SetConsistencyThreshold() on any axis, reads a third time and returns the latest read
+// which agrees with another one. An error is returned if none agree.
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (g *Gyro) ReadConsistent() (r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e := g.updateTempBias(); e != nil {
+		return r3.Vector{}, e
+	}
+	var reads [3]minimu9.IntVector
+	var e error
+	for i := range reads {
+		reads[i], _, e = g.readRawWithStatus()
+		if e != nil && !isDataAvailabilityError(e) {
+			return r3.Vector{}, e
+		}
+		if i == 0 {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if g.consistent(reads[i], reads[j]) {
+				return g.output(reads[i].R3().Mul(scaleRatio[g.fullScaleIndex])), e
+			}
+		}
+	}
+	return r3.Vector{}, errors.New("none of 3 reads are consistent with each other")
+}
+
+// consistent tells whether a and b differ by no more than the consistency threshold on each axis.
+func (g *Gyro) consistent(a, b minimu9.IntVector) bool {
+	for _, d := range []int{
+		int(a.X) - int(b.X), int(a.Y) - int(b.Y), int(a.Z) - int(b.Z),
+	} {
+		if d > g.consistency || d < -g.consistency {
+			return false
+		}
+	}
+	return true
+}
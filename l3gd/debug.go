@@ -0,0 +1,35 @@
+package l3gd
+
+import (
+	"github.com/dasfoo/minimu9"
+)
+
+// dumpRanges are contiguous ranges of registers read by DumpRegisters(), first to last.
+// REFERENCE (0x25) is skipped, because reading it resets the high-pass filter in
+// HPFModeNormalWithReset mode, and INT1_SRC (0x31), because reading it clears a latched interrupt.
+var dumpRanges = [][2]byte{
+	{regWhoAmI, regWhoAmI},
+	{regCtrl1, regReference - 1},
+	{regReference + 1, regInt1Cfg},
+	{regInt1Src + 1, regLowOdr},
+}
+
+// DumpRegisters reads WHO_AM_I, configuration and output registers (except REFERENCE and
+// INT1_SRC), for debugging. Each contiguous range of registers is read in a single transaction.
+// NOTE: reading output registers pops a sample from FIFO when it's enabled.
+func (g *Gyro) DumpRegisters() (map[byte]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	registers := make(map[byte]byte)
+	for _, bounds := range dumpRanges {
+		data := make([]byte, bounds[1]-bounds[0]+1)
+		// Set MSB for the slave to advance the register on every read.
+		if e := minimu9.ReadFullSliceFromReg(g.bus, g.address, bounds[0]|(1<<7), data); e != nil {
+			return nil, e
+		}
+		for i, value := range data {
+			registers[bounds[0]+byte(i)] = value
+		}
+	}
+	return registers, nil
+}
@@ -0,0 +1,59 @@
+package l3gd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/geo/r3"
+)
+
+// DecimatingReader reduces the rate of samples read from Gyro by a constant factor, optionally
+// averaging them. This allows running the sensor at a high data rate for lower noise, while
+// processing samples at a lower rate.
+type DecimatingReader struct {
+	gyro    *Gyro
+	factor  int
+	average bool
+}
+
+// NewDecimatingReader creates a new reader returning every factor-th sample read from g, or,
+// if average is true, an average of every factor samples.
+func NewDecimatingReader(g *Gyro, factor int, average bool) (*DecimatingReader, error) {
+	if factor <= 0 {
+		return nil, fmt.Errorf("decimation factor must be positive, got %d", factor)
+	}
+	return &DecimatingReader{gyro: g, factor: factor, average: average}, nil
+}
+
+// Read waits for and reads the next factor new samples (as returned by Gyro.Read()), and returns
+// either the last of them or their average. Warnings about overwritten data are ignored.
+// Returns ErrNoNewData if a new sample doesn't arrive within 5 data rate periods, same as
+// Gyro.CalibrateBias(), e.g. because the sensor is not measuring.
+func (d *DecimatingReader) Read() (r3.Vector, error) {
+	d.gyro.mu.Lock()
+	timeout := time.Duration(freshTimeoutPeriods * float64(time.Second) / d.gyro.frequency)
+	d.gyro.mu.Unlock()
+	var sum, v r3.Vector
+	deadline := time.Now().Add(timeout)
+	for i := 0; i < d.factor; {
+		var e error
+		v, e = d.gyro.Read()
+		if isDataNotAvailable(e) {
+			if time.Now().After(deadline) {
+				return r3.Vector{}, ErrNoNewData
+			}
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		if e != nil && !isDataAvailabilityError(e) {
+			return r3.Vector{}, e
+		}
+		sum = sum.Add(v)
+		i++
+		deadline = time.Now().Add(timeout)
+	}
+	if d.average {
+		return sum.Mul(1 / float64(d.factor)), nil
+	}
+	return v, nil
+}
@@ -0,0 +1,21 @@
+package l3gd
+
+import (
+	"testing"
+)
+
+func TestDecimatingReaderWithoutNewData(t *testing.T) {
+	bus := newTestBus()
+	bus.SetRegister(testAddress, regStatus, 0)
+	g := NewGyro(bus, testAddress)
+	if _, e := g.SetFrequency(800); e != nil {
+		t.Fatal(e)
+	}
+	d, e := NewDecimatingReader(g, 4, true)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if _, e = d.Read(); e != ErrNoNewData {
+		t.Errorf("expected ErrNoNewData, got %v", e)
+	}
+}
@@ -0,0 +1,219 @@
+package l3gd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+const (
+	regFifoCtrl = 0x2e
+	regFifoSrc  = 0x2f
+)
+
+// fifoSize is the number of samples the FIFO can hold.
+const fifoSize = 32
+
+// FIFOMode is an operating mode of the 32-slot FIFO, see SetFIFOMode().
+type FIFOMode byte
+
+const (
+	// FIFOModeBypass keeps only the latest sample in the output registers. This is the default.
+	FIFOModeBypass FIFOMode = iota
+	// FIFOModeFIFO collects samples until the FIFO is full, then stops.
+	FIFOModeFIFO
+	// FIFOModeStream collects samples continuously, overwriting the oldest when full.
+	FIFOModeStream
+	// FIFOModeStreamToFIFO works as Stream until an interrupt event, then switches to FIFO.
+	FIFOModeStreamToFIFO
+	// FIFOModeBypassToStream works as Bypass until an interrupt event, then switches to Stream.
+	FIFOModeBypassToStream
+)
+
+// SetFIFOMode configures FIFO operating mode. FIFO also has to be enabled with EnableFIFO().
+func (g *Gyro) SetFIFOMode(mode FIFOMode) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if mode > FIFOModeBypassToStream {
+		return fmt.Errorf("unsupported FIFO mode %d", mode)
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regFifoCtrl,
+		(1<<5)|(1<<6)|(1<<7), byte(mode)<<5)
+}
+
+// EnableFIFO turns FIFO on or off.
+func (g *Gyro) EnableFIFO(enable bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var fifoBit byte
+	if enable {
+		fifoBit = 1 << 6
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl5, 1<<6, fifoBit)
+}
+
+// FIFOStatus is a decoded FIFO_SRC register.
+type FIFOStatus struct {
+	// ThresholdReached tells that the number of stored samples has reached the watermark.
+	ThresholdReached bool
+	// Overrun tells that FIFO is full and some samples were lost.
+	Overrun bool
+	// Empty tells that there are no samples stored.
+	Empty bool
+	// Stored is the number of samples stored, 0..32.
+	Stored int
+}
+
+func decodeFIFOStatus(fifoSrc byte) FIFOStatus {
+	status := FIFOStatus{
+		ThresholdReached: fifoSrc&(1<<7) != 0,
+		Overrun:          fifoSrc&(1<<6) != 0,
+		Empty:            fifoSrc&(1<<5) != 0,
+		Stored:           int(fifoSrc & 0x1f),
+	}
+	if status.Overrun {
+		status.Stored = fifoSize
+	} else if status.Empty {
+		status.Stored = 0
+	}
+	return status
+}
+
+// ReadFIFO drains the samples stored in FIFO, oldest first, in degrees per second
+// (same as ReadTimed() returns). It takes two transactions regardless of the number of samples:
+// one to read the stored sample count from FIFO_SRC, and another to read all the samples in
+// a single burst, unless burst transfers are disabled with SetBurstTransfers().
+// An empty FIFO results in an empty slice, not an error.
+// Note: err might be a minimu9.DataAvailabilityError warning if FIFO has overrun and
+// some samples were lost; the returned samples are still valid in that case. The number of
+// lost samples is estimated from the time since the previous ReadFIFO(), if any. With
+// SetFIFOAutoRecover(), FIFO is also recovered with RecoverFIFO() then.
+func (g *Gyro) ReadFIFO() ([]r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	samples := make([]r3.Vector, fifoSize)
+	n, e := g.readFIFOInto(samples)
+	if e != nil && !isDataAvailabilityError(e) {
+		return nil, e
+	}
+	return samples[:n], e
+}
+
+// ReadFIFOInto is the same as ReadFIFO(), but stores the samples into dst and returns the number
+// of samples stored, so that draining FIFO does not allocate unless there's an error or a warning.
+// If dst is too small, only len(dst) oldest samples are drained, and the rest stay in FIFO for
+// the next call (FIFO is not recovered then); a slice of 32 samples always fits the whole FIFO.
+func (g *Gyro) ReadFIFOInto(dst []r3.Vector) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.readFIFOInto(dst)
+}
+
+func (g *Gyro) readFIFOInto(dst []r3.Vector) (int, error) {
+	fifoSrc, e := g.bus.ReadByteFromReg(g.address, regFifoSrc)
+	if e != nil {
+		return 0, e
+	}
+	if e = g.updateTempBias(); e != nil {
+		return 0, e
+	}
+	previousRead := g.fifoReadTime
+	g.fifoReadTime = time.Now()
+	status := decodeFIFOStatus(fifoSrc)
+	count := status.Stored
+	if count > len(dst) {
+		count = len(dst)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	data := g.fifoBuffer[:count*6]
+	// Set MSB for the slave to advance the register on every read. In a burst transfer, the address
+	// wraps around to OUT_X_L after OUT_Z_H, popping the next FIFO slot. Without burst transfers
+	// it does not, so samples have to be read one by one.
+	chunk := len(data)
+	if g.singleByte {
+		chunk = 6
+	}
+	for offset := 0; offset < len(data); offset += chunk {
+		if e = minimu9.ReadFullSliceFromReg(g.bus, g.address, regOutX|(1<<7),
+			data[offset:offset+chunk]); e != nil {
+			return 0, e
+		}
+	}
+	for i := 0; i < count; i++ {
+		v := minimu9.DecodeVector(data[i*6:i*6+6], g.byteOrder)
+		dst[i] = g.output(v.R3().Mul(scaleRatio[g.fullScaleIndex]))
+	}
+	if status.Overrun {
+		dataErr := &minimu9.DataAvailabilityError{NewDataWasOverwritten: true}
+		if !previousRead.IsZero() {
+			measured := int(g.fifoReadTime.Sub(previousRead).Seconds() * g.frequency)
+			if measured > status.Stored {
+				dataErr.SamplesLost = measured - status.Stored
+			}
+		}
+		g.logf("FIFO overrun, %d samples read, about %d lost", count, dataErr.SamplesLost)
+		if g.fifoRecover && count == status.Stored {
+			if e = g.recoverFIFO(); e != nil {
+				return count, e
+			}
+		}
+		return count, dataErr
+	}
+	return count, nil
+}
+
+// RecoverFIFO restarts FIFO after an overrun, as recommended by the datasheet: switches it
+// to Bypass mode, which empties FIFO and resets the overrun flag, and back to the mode set with
+// SetFIFOMode(). In FIFO mode, this is the only way to resume collecting samples.
+func (g *Gyro) RecoverFIFO() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.recoverFIFO()
+}
+
+func (g *Gyro) recoverFIFO() error {
+	fifoCtrl, e := g.bus.ReadByteFromReg(g.address, regFifoCtrl)
+	if e != nil {
+		return e
+	}
+	if FIFOMode(fifoCtrl>>5) == FIFOModeBypass {
+		return nil
+	}
+	// Keep the watermark.
+	if e = g.bus.WriteByteToReg(g.address, regFifoCtrl, fifoCtrl&0x1f); e != nil {
+		return e
+	}
+	return g.bus.WriteByteToReg(g.address, regFifoCtrl, fifoCtrl)
+}
+
+// SetFIFOAutoRecover makes ReadFIFO() call RecoverFIFO() whenever FIFO has overrun, e.g. when the
+// host occasionally stalls between reads. It is off by default.
+func (g *Gyro) SetFIFOAutoRecover(enable bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.fifoRecover = enable
+}
+
+// SetFIFOThreshold sets FIFO watermark level, 0..31 samples. FIFOThresholdReached() reports when
+// the number of stored samples reaches the watermark; it can also be routed to an interrupt pin.
+func (g *Gyro) SetFIFOThreshold(n int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if n < 0 || n >= fifoSize {
+		return fmt.Errorf("FIFO threshold %d is out of range 0..%d", n, fifoSize-1)
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regFifoCtrl, 0x1f, byte(n))
+}
+
+// FIFOThresholdReached tells whether the FIFO fill level has reached the watermark
+// set with SetFIFOThreshold().
+func (g *Gyro) FIFOThresholdReached() (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fifoSrc, e := g.bus.ReadByteFromReg(g.address, regFifoSrc)
+	return fifoSrc&(1<<7) != 0, e
+}
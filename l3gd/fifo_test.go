@@ -0,0 +1,85 @@
+package l3gd
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/dasfoo/minimu9/minimu9test"
+	"github.com/golang/geo/r3"
+)
+
+// countingBus is a fake bus which counts bus transactions, which dominate the time of a read on
+// real hardware.
+type countingBus struct {
+	*minimu9test.Bus
+	transactions int64
+}
+
+func (b *countingBus) ReadByteFromReg(addr, reg byte) (byte, error) {
+	atomic.AddInt64(&b.transactions, 1)
+	return b.Bus.ReadByteFromReg(addr, reg)
+}
+
+func (b *countingBus) ReadSliceFromReg(addr, reg byte, data []byte) (int, error) {
+	atomic.AddInt64(&b.transactions, 1)
+	return b.Bus.ReadSliceFromReg(addr, reg, data)
+}
+
+// newFIFOBenchmark returns a fake bus with 31 samples stored in FIFO, the most that doesn't
+// overrun, and a gyro on it.
+func newFIFOBenchmark() (*countingBus, *Gyro) {
+	bus := &countingBus{Bus: newTestBus()}
+	bus.SetRegister(testAddress, regFifoSrc, 0x1f)
+	return bus, NewGyro(bus, testAddress)
+}
+
+func reportTransactions(b *testing.B, bus *countingBus) {
+	b.Logf("%.1f transactions/op", float64(atomic.LoadInt64(&bus.transactions))/float64(b.N))
+}
+
+func benchmarkReadFIFO(b *testing.B, burst bool) {
+	bus, g := newFIFOBenchmark()
+	g.SetBurstTransfers(burst)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, e := g.ReadFIFO(); e != nil {
+			b.Fatal(e)
+		}
+	}
+	reportTransactions(b, bus)
+}
+
+func BenchmarkReadFIFOBurst(b *testing.B) {
+	benchmarkReadFIFO(b, true)
+}
+
+func BenchmarkReadFIFOSingleByte(b *testing.B) {
+	benchmarkReadFIFO(b, false)
+}
+
+// BenchmarkReadFIFOPerSample drains FIFO the way ReadFIFO() would without a burst read: reading
+// FIFO_SRC, then each sample in a separate 6-byte transaction.
+func BenchmarkReadFIFOPerSample(b *testing.B) {
+	bus, g := newFIFOBenchmark()
+	var data [6]byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fifoSrc, e := bus.ReadByteFromReg(testAddress, regFifoSrc)
+		if e != nil {
+			b.Fatal(e)
+		}
+		samples := make([]r3.Vector, 0, fifoSize)
+		for j := 0; j < decodeFIFOStatus(fifoSrc).Stored; j++ {
+			if e = minimu9.ReadFullSliceFromReg(bus, testAddress, regOutX|(1<<7),
+				data[:]); e != nil {
+				b.Fatal(e)
+			}
+			v := minimu9.DecodeVector(data[:], g.byteOrder)
+			samples = append(samples, g.output(v.R3().Mul(scaleRatio[g.fullScaleIndex])))
+		}
+	}
+	reportTransactions(b, bus)
+}
@@ -0,0 +1,194 @@
+package l3gd
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dasfoo/minimu9"
+)
+
+const (
+	regCtrl2     = 0x21
+	regReference = 0x25
+)
+
+// HPFMode is a high-pass filter mode, see SetHighPassFilter().
+type HPFMode byte
+
+const (
+	// HPFModeNormalWithReset is a normal mode; reading REFERENCE register resets the filter.
+	// This is the default.
+	HPFModeNormalWithReset HPFMode = iota
+	// HPFModeReference subtracts the value of REFERENCE register from the output.
+	HPFModeReference
+	// HPFModeNormal is a normal mode.
+	HPFModeNormal
+	// HPFModeAutoreset resets the filter automatically on an interrupt event, see
+	// EnableMotionAutoreset().
+	HPFModeAutoreset
+)
+
+// highPassCutoffs is a number of HPCF selections supported by the sensor.
+const highPassCutoffs = 10
+
+// SetHighPassFilter configures high-pass filter mode and cutoff selection (HPCF), 0..9.
+// The actual cutoff frequency depends on the output data rate and is, in Hz:
+//
+//	HPCF  12.5Hz  25Hz   50Hz   100Hz  200Hz  400Hz  800Hz
+//	0     1       2      4      8      15     30     56
+//	1     0.5     1      2      4      8      15     30
+//	2     0.2     0.5    1      2      4      8      15
+//	3     0.1     0.2    0.5    1      2      4      8
+//	4     0.05    0.1    0.2    0.5    1      2      4
+//	5     0.02    0.05   0.1    0.2    0.5    1      2
+//	6     0.01    0.02   0.05   0.1    0.2    0.5    1
+//	7     0.005   0.01   0.02   0.05   0.1    0.2    0.5
+//	8     0.002   0.005  0.01   0.02   0.05   0.1    0.2
+//	9     0.001   0.002  0.005  0.01   0.02   0.05   0.1
+//
+// The filter only affects the output after EnableHighPassFilterOutput(true).
+func (g *Gyro) SetHighPassFilter(mode HPFMode, cutoff int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if mode > HPFModeAutoreset {
+		return fmt.Errorf("unsupported high-pass filter mode %d", mode)
+	}
+	if cutoff < 0 || cutoff >= highPassCutoffs {
+		return fmt.Errorf("high-pass filter cutoff selection %d is out of range 0..%d",
+			cutoff, highPassCutoffs-1)
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl2, 0x3f, byte(mode)<<4|byte(cutoff))
+}
+
+// EnableMotionAutoreset sets up the high-pass filter in HPFModeAutoreset mode with the cutoff
+// selection (HPCF, see SetHighPassFilter()), feeds high-pass filtered data to the interrupt
+// generator and configures the threshold interrupt with ConfigureThresholdInterrupt(), so that
+// every time the interrupt fires (e.g. on a gesture), the filter is reset and the next event is
+// detected from a fresh baseline. The output is only high-pass filtered if it goes through the
+// low-pass filter, see EnableHighPassFilterOutput() and SetBandwidth().
+// The reset happens when the interrupt fires, i.e. after the event has persisted for
+// config.Duration samples (see SetInterruptDuration()), so a longer duration delays the reset as
+// well. The REFERENCE register is not used in this mode: neither subtracted from the output, as
+// in HPFModeReference, nor resetting the filter when read, as in HPFModeNormalWithReset.
+// Disable it with SetHighPassFilter() and ConfigureThresholdInterrupt().
+func (g *Gyro) EnableMotionAutoreset(cutoff int, config ThresholdInterrupt) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if cutoff < 0 || cutoff >= highPassCutoffs {
+		return fmt.Errorf("high-pass filter cutoff selection %d is out of range 0..%d",
+			cutoff, highPassCutoffs-1)
+	}
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl2, 0x3f,
+		byte(HPFModeAutoreset)<<4|byte(cutoff)); e != nil {
+		return e
+	}
+	// HPen, IG_Sel = 01: high-pass filtered data for the interrupt generator.
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl5, (1<<4)|(1<<3)|(1<<2),
+		(1<<4)|(1<<2)); e != nil {
+		return e
+	}
+	return g.configureThresholdInterrupt(config)
+}
+
+// EnableHighPassFilterOutput routes data through the high-pass filter (and then the low-pass
+// filter set by bandwidth) into the output registers and FIFO, or restores the default path
+// which bypasses both, so SetBandwidth() has to be called again to keep the low-pass filter.
+// Filter mode and cutoff are configured with SetHighPassFilter().
+func (g *Gyro) EnableHighPassFilterOutput(enable bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var filterBits byte
+	if enable {
+		// HPen, Out_Sel = 10.
+		filterBits = (1 << 4) | (1 << 1)
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl5, (1<<4)|(1<<1)|(1<<0), filterBits)
+}
+
+// bandwidths are low-pass filter cutoffs, in Hz, for each BW setting (columns) at each data rate
+// (rows, same as frequencies). There's no configurable cutoff at 12.5 and 25 Hz.
+var bandwidths = [][]float64{
+	nil,
+	nil,
+	{16.6, 16.6, 16.6, 16.6},
+	{12.5, 25, 25, 25},
+	{12.5, 25, 50, 70},
+	{20, 25, 50, 110},
+	{30, 35, 50, 100},
+}
+
+// SetBandwidth sets low-pass filter cutoff, in Hz, choosing the closest one available at the
+// current data rate (so it has to be called after SetFrequency()). Available cutoffs, in Hz:
+//
+//	12.5Hz, 25Hz: not configurable
+//	50Hz:  16.6
+//	100Hz: 12.5, 25
+//	200Hz: 12.5, 25, 50, 70
+//	400Hz: 20, 25, 50, 110
+//	800Hz: 30, 35, 50, 100
+//
+// The data is only filtered by the configurable low-pass filter (LPF2) if it is routed to the
+// output, which the default output path (Out_Sel = 00) bypasses, so SetBandwidth() also routes
+// the output (and FIFO) through LPF2 (Out_Sel = 10), without enabling the high-pass filter
+// unless it was enabled with EnableHighPassFilterOutput().
+func (g *Gyro) SetBandwidth(hz int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if hz <= 0 {
+		return fmt.Errorf("bandwidth must be positive, got %d Hz", hz)
+	}
+	cutoffs := bandwidths[frequencyIndex(g.frequency)]
+	if cutoffs == nil {
+		return fmt.Errorf("bandwidth is not configurable at %v Hz data rate", g.frequency)
+	}
+	bandwidthBits := 0
+	for index, cutoff := range cutoffs {
+		if math.Abs(cutoff-float64(hz)) < math.Abs(cutoffs[bandwidthBits]-float64(hz)) {
+			bandwidthBits = index
+		}
+	}
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl1,
+		(1<<4)|(1<<5), byte(bandwidthBits)<<4); e != nil {
+		return e
+	}
+	// Out_Sel = 10, keeping HPen.
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl5, (1<<1)|(1<<0), 1<<1)
+}
+
+// Bandwidth reads the actual low-pass filter cutoff, in Hz, decoding the BW bits of CTRL1 for the
+// data rate set in CTRL1 and LOW_ODR, see SetBandwidth(). Where the cutoff is not configurable
+// (12.5 and 25 Hz data rates), or the output bypasses the configurable filter (CTRL5 Out_Sel,
+// see SetBandwidth()), the Nyquist frequency is returned.
+func (g *Gyro) Bandwidth() (float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.readBandwidth()
+}
+
+// decodeBandwidth returns low-pass filter cutoff, in Hz, set by CTRL1, LOW_ODR and CTRL5 values.
+func decodeBandwidth(ctrl1, lowOdr, ctrl5 byte) float64 {
+	index := decodeFrequencyIndex(ctrl1, lowOdr)
+	// Out_Sel = 1x routes the output through LPF2.
+	if bandwidths[index] == nil || ctrl5&(1<<1) == 0 {
+		return frequencies[index] / 2
+	}
+	return bandwidths[index][(ctrl1>>4)&0x03]
+}
+
+// SetHighPassReference sets the value subtracted from the output by the high-pass filter in
+// HPFModeReference mode, in raw counts. The register is 8-bit, so the whole int8 range,
+// -128..127, is accepted.
+func (g *Gyro) SetHighPassReference(v int8) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.bus.WriteByteToReg(g.address, regReference, byte(v))
+}
+
+// HighPassReference reads the high-pass filter reference value, in raw counts.
+// NOTE: in HPFModeNormalWithReset mode, reading it resets the filter.
+func (g *Gyro) HighPassReference() (int8, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	v, e := g.bus.ReadByteFromReg(g.address, regReference)
+	return int8(v), e
+}
@@ -0,0 +1,131 @@
+package l3gd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+// healthNoiseSamples is how many samples HealthCheck() uses to measure noise.
+const healthNoiseSamples = 50
+
+// HealthReport is a result of HealthCheck(). Each check is reported separately, so that a partial
+// failure can be diagnosed; an Error field is nil if the check could be completed.
+type HealthReport struct {
+	// IdentityError is an error returned by Check().
+	IdentityError error
+	// SelfTestPassed and SelfTestError are results of SelfTest(). SelfTestError is ErrNoNewData
+	// if the sensor is not measuring.
+	SelfTestPassed bool
+	SelfTestError  error
+	// Noise and NoiseError are results of MeasureNoise(), in degrees per second. NoiseError is
+	// ErrNoNewData if the sensor is not measuring.
+	Noise      r3.Vector
+	NoiseError error
+	// Temperature and TemperatureError are results of ReadTemperature().
+	Temperature      int
+	TemperatureError error
+}
+
+// HealthCheck verifies WHO_AM_I, runs self-test, measures noise and reads temperature, e.g. to
+// confirm that a board is good before deploying it. The checks after WHO_AM_I are still run if
+// it fails, in case another compatible sensor is used. Returns the report, and an error
+// describing the first failed check, if any.
+// If the sensor is not measuring (e.g. it's not in normal mode, see SetFrequency()), the report
+// tells so rather than HealthCheck() blocking.
+// NOTE: the sensor has to be static (not moving).
+func (g *Gyro) HealthCheck() (HealthReport, error) {
+	var r HealthReport
+	r.IdentityError = g.Check()
+	r.SelfTestPassed, r.SelfTestError = g.SelfTest()
+	r.Noise, r.NoiseError = g.MeasureNoise(healthNoiseSamples)
+	r.Temperature, r.TemperatureError = g.ReadTemperature()
+	switch {
+	case r.IdentityError != nil:
+		return r, fmt.Errorf("identity check failed: %v", r.IdentityError)
+	case r.SelfTestError != nil:
+		return r, fmt.Errorf("self-test failed: %v", r.SelfTestError)
+	case !r.SelfTestPassed:
+		return r, errors.New("self-test failed: output change is out of range")
+	case r.NoiseError != nil:
+		return r, fmt.Errorf("noise measurement failed: %v", r.NoiseError)
+	case r.TemperatureError != nil:
+		return r, fmt.Errorf("temperature read failed: %v", r.TemperatureError)
+	}
+	return r, nil
+}
+
+// IsResponsive verifies WHO_AM_I with Check(), then reads raw counts twice, interval apart, and
+// tells whether they differ by at least minChange counts on any axis. Since the output of a
+// working sensor always changes due to noise, equal readings mean that the sensor (or the bus)
+// is stuck returning the same value, which WHO_AM_I check alone won't catch. Choose minChange
+// according to MeasureNoise() for the full scale in use; 1 works in most cases. The interval has
+// to be longer than the data rate period. Gyro is not locked while waiting.
+func (g *Gyro) IsResponsive(interval time.Duration, minChange int) (bool, error) {
+	if minChange <= 0 {
+		return false, fmt.Errorf("minimum change must be positive, got %d", minChange)
+	}
+	if e := g.Check(); e != nil {
+		return false, e
+	}
+	first, e := g.readRawLocked()
+	if e != nil {
+		return false, e
+	}
+	time.Sleep(interval)
+	second, e := g.readRawLocked()
+	if e != nil {
+		return false, e
+	}
+	for _, change := range []int{
+		int(second.X) - int(first.X), int(second.Y) - int(first.Y), int(second.Z) - int(first.Z),
+	} {
+		if change >= minChange || -change >= minChange {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MeasureActualRate reads the sensor as fast as possible for the specified duration, and returns
+// the number of new samples received per second. It can be well below Frequency() if the bus or
+// the host can't keep up, in which case samples are overwritten before they are read. Each read
+// is a separate transaction, same as Read(), and Gyro is only locked for the time of each read.
+func (g *Gyro) MeasureActualRate(duration time.Duration) (float64, error) {
+	if duration <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %v", duration)
+	}
+	read := func() error {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		_, _, e := g.readRawWithStatus()
+		return e
+	}
+	fresh := 0
+	start := time.Now()
+	for time.Since(start) < duration {
+		e := read()
+		if isDataNotAvailable(e) {
+			continue
+		}
+		if e != nil && !isDataAvailabilityError(e) {
+			return 0, e
+		}
+		fresh++
+	}
+	return float64(fresh) / time.Since(start).Seconds(), nil
+}
+
+// readRawLocked reads raw counts, ignoring data "freshness".
+func (g *Gyro) readRawLocked() (minimu9.IntVector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	v, _, e := g.readRawWithStatus()
+	if isDataAvailabilityError(e) {
+		e = nil
+	}
+	return v, e
+}
@@ -0,0 +1,30 @@
+package l3gd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeasureActualRateWithoutNewData(t *testing.T) {
+	bus := newTestBus()
+	bus.SetRegister(testAddress, regStatus, 0)
+	g := NewGyro(bus, testAddress)
+	rate, e := g.MeasureActualRate(10 * time.Millisecond)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if rate != 0 {
+		t.Errorf("expected rate 0 without new data, got %v", rate)
+	}
+}
+
+func TestMeasureActualRateWithNewData(t *testing.T) {
+	g := NewGyro(newTestBus(), testAddress)
+	rate, e := g.MeasureActualRate(10 * time.Millisecond)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if rate <= 0 {
+		t.Errorf("expected a positive rate with new data on every read, got %v", rate)
+	}
+}
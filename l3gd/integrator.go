@@ -0,0 +1,148 @@
+package l3gd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+// Integrator accumulates angular speed read from Gyro into rotation angles, in degrees,
+// using trapezoidal integration, and into orientation quaternion. Drift compensation is up to
+// the user.
+type Integrator struct {
+	gyro        *Gyro
+	angle       r3.Vector
+	orientation Quaternion
+	previous    r3.Vector
+	started     bool
+	pending     time.Duration
+	decay       float64
+}
+
+// Quaternion is a rotation quaternion W + Xi + Yj + Zk.
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+var identityQuaternion = Quaternion{W: 1}
+
+// Mul returns the Hamilton product q * r, which rotates vectors by r first, then by q.
+func (q Quaternion) Mul(r Quaternion) Quaternion {
+	return Quaternion{
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+	}
+}
+
+// Normalize returns q scaled to unit length.
+func (q Quaternion) Normalize() Quaternion {
+	n := math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+	return Quaternion{W: q.W / n, X: q.X / n, Y: q.Y / n, Z: q.Z / n}
+}
+
+// rotationQuaternion returns a quaternion for rotation by angular speed omega, in radians per
+// second, over dt seconds.
+func rotationQuaternion(omega r3.Vector, dt float64) Quaternion {
+	angle := omega.Norm() * dt
+	if angle == 0 {
+		return identityQuaternion
+	}
+	axis := omega.Normalize().Mul(math.Sin(angle / 2))
+	return Quaternion{W: math.Cos(angle / 2), X: axis.X, Y: axis.Y, Z: axis.Z}
+}
+
+// NewIntegrator creates a new integrator reading from g.
+func NewIntegrator(g *Gyro) *Integrator {
+	return &Integrator{gyro: g, orientation: identityQuaternion}
+}
+
+// Update reads a sample and integrates it over dt, the time passed since the previous Update().
+// If there's no new sample yet, dt is carried over to the next Update() with a new sample,
+// so that a single sample is never integrated twice.
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (i *Integrator) Update(dt time.Duration) error {
+	v, e := i.gyro.ReadDPS()
+	i.pending += dt
+	if isDataNotAvailable(e) {
+		return e
+	}
+	if _, ok := e.(*minimu9.DataAvailabilityError); e != nil && !ok {
+		return e
+	}
+	i.integrate(v, i.pending.Seconds())
+	i.pending = 0
+	return e
+}
+
+// IntegrateFIFO drains FIFO with Gyro.ReadFIFO() and integrates every sample over the data rate
+// period, so that no samples are skipped even if the host can't keep up with polling, as long as
+// FIFO doesn't overrun. FIFO has to be set up in advance, e.g. in Stream mode. Returns Angle().
+// Do not mix it with Update(), which also consumes samples.
+// Note: err might be a minimu9.DataAvailabilityError warning if FIFO has overrun; the samples
+// read are still integrated in that case.
+func (i *Integrator) IntegrateFIFO() (r3.Vector, error) {
+	samples, e := i.gyro.ReadFIFO()
+	if e != nil && !isDataAvailabilityError(e) {
+		return i.angle, e
+	}
+	i.gyro.mu.Lock()
+	period := 1 / i.gyro.frequency
+	i.gyro.mu.Unlock()
+	for _, v := range samples {
+		i.integrate(v, period)
+	}
+	return i.angle, e
+}
+
+// integrate adds rotation by angular speed v, in degrees per second, over dt seconds since the
+// previous sample.
+func (i *Integrator) integrate(v r3.Vector, dt float64) {
+	if !i.started {
+		i.previous = v
+		i.started = true
+	}
+	i.angle = i.angle.Mul(1 - i.decay).Add(v.Add(i.previous).Mul(dt / 2))
+	// Renormalize on every update to keep rounding errors from accumulating.
+	i.orientation = i.orientation.Mul(rotationQuaternion(
+		v.Add(i.previous).Mul(math.Pi/180/2), dt)).Normalize()
+	i.previous = v
+}
+
+// SetDriftDecay makes the accumulated angles leak towards zero by the fraction alpha (0..1) on
+// every integrated sample (see Update() and IntegrateFIFO()). This bounds the drift, at the cost
+// of absolute accuracy, so it's only suitable when short-term relative rotation matters.
+// Defaults to 0 (no decay).
+func (i *Integrator) SetDriftDecay(alpha float64) error {
+	if alpha < 0 || alpha > 1 {
+		return fmt.Errorf("drift decay %v is out of range 0..1", alpha)
+	}
+	i.decay = alpha
+	return nil
+}
+
+// Angle returns rotation angles around each axis accumulated since the last Reset(), in degrees.
+// They only describe orientation well for small rotations, or rotations about a single axis;
+// use Quaternion() otherwise.
+func (i *Integrator) Angle() r3.Vector {
+	return i.angle
+}
+
+// Quaternion returns orientation of the sensor relative to its orientation at the last Reset(),
+// as a unit quaternion rotating vectors from the current sensor frame into the sensor frame at
+// Reset(). Unlike Angle(), it stays accurate for large rotations about several axes.
+func (i *Integrator) Quaternion() Quaternion {
+	return i.orientation
+}
+
+// Reset sets accumulated angles to zero and orientation to identity.
+func (i *Integrator) Reset() {
+	i.angle = r3.Vector{}
+	i.orientation = identityQuaternion
+	i.started = false
+	i.pending = 0
+}
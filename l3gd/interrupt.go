@@ -0,0 +1,335 @@
+package l3gd
+
+import (
+	"fmt"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+const (
+	regCtrl3        = 0x22
+	regInt1Cfg      = 0x30
+	regInt1Src      = 0x31
+	regInt1ThsXH    = 0x32
+	regInt1ThsZL    = 0x37
+	regInt1Duration = 0x38
+)
+
+// EnableDataReadyInterrupt makes the sensor assert DRDY/INT2 pin whenever new data is available,
+// so that the host can wait for a GPIO interrupt instead of polling Read().
+// FIFO interrupts share this pin but are enabled independently with EnableFIFOInterrupts().
+func (g *Gyro) EnableDataReadyInterrupt(enable bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var drdyBit byte
+	if enable {
+		drdyBit = 1 << 3
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl3, 1<<3, drdyBit)
+}
+
+// SetInterruptPins configures the electrical behavior of both interrupt pins:
+// active high (default) or active low, push-pull (default) or open drain.
+func (g *Gyro) SetInterruptPins(activeLow, openDrain bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e := g.setDataReadyActiveLow(activeLow); e != nil {
+		return e
+	}
+	var pinBits byte
+	if activeLow {
+		pinBits |= 1 << 5
+	}
+	if openDrain {
+		pinBits |= 1 << 4
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl3, (1<<4)|(1<<5), pinBits)
+}
+
+// setDataReadyActiveLow sets polarity of DRDY/INT2 pin (DRDY_HL bit of LOW_ODR), which, unlike
+// INT1, is not covered by H_Lactive bit of CTRL3.
+func (g *Gyro) setDataReadyActiveLow(activeLow bool) error {
+	var drdyHlBit byte
+	if activeLow {
+		drdyHlBit = 1 << 5
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regLowOdr, 1<<5, drdyHlBit)
+}
+
+// EnableFIFOInterrupts selects FIFO events which assert DRDY/INT2 pin: FIFO reaching the
+// watermark set by SetFIFOThreshold(), FIFO overrun, and FIFO becoming empty.
+func (g *Gyro) EnableFIFOInterrupts(watermark, overrun, empty bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var fifoBits byte
+	if watermark {
+		fifoBits |= 1 << 2
+	}
+	if overrun {
+		fifoBits |= 1 << 1
+	}
+	if empty {
+		fifoBits |= 1 << 0
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl3, 0x07, fifoBits)
+}
+
+// ThresholdInterrupt is a configuration of the angular rate threshold interrupt on INT1 pin.
+type ThresholdInterrupt struct {
+	// XHigh, XLow, YHigh, YLow, ZHigh and ZLow enable interrupt events when the angular rate on
+	// the axis goes above (High) or below (Low) the axis threshold.
+	XHigh, XLow, YHigh, YLow, ZHigh, ZLow bool
+	// And makes the interrupt fire only when all the enabled events occur; otherwise any will do.
+	And bool
+	// Threshold for each axis, in raw counts at the current full scale: 0..32767.
+	Threshold minimu9.IntVector
+	// ThresholdDPS, if not zero, is used instead of Threshold: threshold for each axis, in degrees
+	// per second, converted with DPSToCounts() at the current full scale.
+	ThresholdDPS r3.Vector
+	// Duration is for how many samples (ODR periods) an event must persist to fire: 0..127,
+	// see SetInterruptDuration().
+	Duration byte
+}
+
+// maxThreshold is the maximum value of a 15-bit interrupt threshold.
+const maxThreshold = 0x7fff
+
+// ConfigureThresholdInterrupt configures and routes to INT1 pin the threshold interrupt, e.g.
+// to detect sudden rotation without polling. The interrupt is disabled if no events are enabled.
+// Use ThresholdInterruptSource() to find out which event has fired.
+func (g *Gyro) ConfigureThresholdInterrupt(config ThresholdInterrupt) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.configureThresholdInterrupt(config)
+}
+
+func (g *Gyro) configureThresholdInterrupt(config ThresholdInterrupt) error {
+	if config.ThresholdDPS != (r3.Vector{}) {
+		var counts [3]int16
+		for axis, dps := range []float64{
+			config.ThresholdDPS.X, config.ThresholdDPS.Y, config.ThresholdDPS.Z,
+		} {
+			threshold := g.dpsToCounts(dps)
+			if threshold < 0 || threshold > maxThreshold {
+				return fmt.Errorf("interrupt threshold %v degrees/s is out of range 0..%v",
+					dps, g.countsToDPS(maxThreshold))
+			}
+			counts[axis] = int16(threshold)
+		}
+		config.Threshold = minimu9.IntVector{X: counts[0], Y: counts[1], Z: counts[2]}
+	}
+	for _, threshold := range []int16{config.Threshold.X, config.Threshold.Y, config.Threshold.Z} {
+		if threshold < 0 {
+			return fmt.Errorf("interrupt threshold %d is out of range 0..%d", threshold, maxThreshold)
+		}
+	}
+	if config.Duration > maxInterruptDuration {
+		return fmt.Errorf("interrupt duration %d is out of range 0..%d",
+			config.Duration, maxInterruptDuration)
+	}
+	thresholds := []uint16{
+		uint16(config.Threshold.X), uint16(config.Threshold.Y), uint16(config.Threshold.Z),
+	}
+	for axis, threshold := range thresholds {
+		reg := regInt1ThsXH + byte(axis)*2
+		// Keep the DCRM bit in the high byte.
+		if e := minimu9.WriteBitsToReg(g.bus, g.address, reg, 0x7f, byte(threshold>>8)); e != nil {
+			return e
+		}
+		if e := g.bus.WriteByteToReg(g.address, reg+1, byte(threshold)); e != nil {
+			return e
+		}
+	}
+	// Keep the WAIT bit.
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regInt1Duration,
+		maxInterruptDuration, config.Duration); e != nil {
+		return e
+	}
+	var cfgBits byte
+	for bit, enabled := range []bool{
+		config.XLow, config.XHigh, config.YLow, config.YHigh, config.ZLow, config.ZHigh,
+	} {
+		if enabled {
+			cfgBits |= 1 << uint(bit)
+		}
+	}
+	var routeBit byte
+	if cfgBits != 0 {
+		routeBit = 1 << 7
+	}
+	if config.And {
+		cfgBits |= 1 << 7
+	}
+	// Keep the LIR bit.
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regInt1Cfg, ^byte(1<<6), cfgBits); e != nil {
+		return e
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl3, 1<<7, routeBit)
+}
+
+// SetInterruptLatched selects whether the threshold interrupt stays asserted until INT1_SRC is
+// read (e.g. with ThresholdInterruptSource()), or follows the condition, which is the default.
+func (g *Gyro) SetInterruptLatched(latched bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var lirBit byte
+	if latched {
+		lirBit = 1 << 6
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regInt1Cfg, 1<<6, lirBit)
+}
+
+// InterruptPin is a physical interrupt pin of the sensor, see SetInterruptRouting().
+type InterruptPin int
+
+const (
+	// InterruptPinNone disconnects the signal from both pins.
+	InterruptPinNone InterruptPin = iota
+	// InterruptPinINT1 is INT1 pin.
+	InterruptPinINT1
+	// InterruptPinINT2 is DRDY/INT2 pin.
+	InterruptPinINT2
+)
+
+// SetInterruptRouting selects which pins carry data-ready and threshold interrupts. The sensor can
+// only route data-ready to DRDY/INT2 and threshold interrupt to INT1, so other pins are rejected
+// with an error; on boards which only break out INT1, data-ready has to be polled with Status().
+// This is the same as EnableDataReadyInterrupt() and the routing done by
+// ConfigureThresholdInterrupt().
+func (g *Gyro) SetInterruptRouting(dataReady, threshold InterruptPin) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var routeBits byte
+	switch dataReady {
+	case InterruptPinNone:
+	case InterruptPinINT2:
+		routeBits |= 1 << 3
+	default:
+		return fmt.Errorf("data-ready interrupt can't be routed to pin %d, only to DRDY/INT2",
+			dataReady)
+	}
+	switch threshold {
+	case InterruptPinNone:
+	case InterruptPinINT1:
+		routeBits |= 1 << 7
+	default:
+		return fmt.Errorf("threshold interrupt can't be routed to pin %d, only to INT1", threshold)
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl3, (1<<7)|(1<<3), routeBits)
+}
+
+// InterruptConfig is the electrical and latching behavior of the interrupt pins.
+type InterruptConfig struct {
+	// ActiveLow makes both pins active low (H_Lactive for INT1 and DRDY_HL for DRDY/INT2);
+	// otherwise they are active high, which is the default.
+	ActiveLow bool
+	// OpenDrain makes both pins open drain (requiring a pull-up when active low); otherwise
+	// they are push-pull, which is the default.
+	OpenDrain bool
+	// Latched keeps the threshold interrupt on INT1 asserted until INT1_SRC is read, see
+	// SetInterruptLatched().
+	Latched bool
+}
+
+// ConfigureInterruptPins sets the electrical and latching behavior of the interrupt pins at once,
+// so that it can be matched to the host GPIO, e.g. an input with a pull-up needs an open drain
+// active low pin. It is the same as calling SetInterruptPins() and SetInterruptLatched().
+func (g *Gyro) ConfigureInterruptPins(config InterruptConfig) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var pinBits, lirBit byte
+	if config.ActiveLow {
+		pinBits |= 1 << 5
+	}
+	if config.OpenDrain {
+		pinBits |= 1 << 4
+	}
+	if config.Latched {
+		lirBit = 1 << 6
+	}
+	if e := g.setDataReadyActiveLow(config.ActiveLow); e != nil {
+		return e
+	}
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl3, (1<<4)|(1<<5), pinBits); e != nil {
+		return e
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regInt1Cfg, 1<<6, lirBit)
+}
+
+// maxInterruptDuration is the maximum value of the 7-bit interrupt duration.
+const maxInterruptDuration = 0x7f
+
+// SetInterruptDuration sets for how many samples, 0..127, a threshold interrupt event must persist
+// to fire (debounce), e.g. to detect sustained rotation rather than a momentary spike. Samples
+// are counted in ODR periods, so the time window is samples / Frequency() seconds, e.g. 50
+// samples at 100 Hz are 0.5s. With wait, the interrupt is also deasserted only after the event
+// has been gone for the same duration; otherwise it is deasserted immediately.
+// The duration is also set by ConfigureThresholdInterrupt().
+func (g *Gyro) SetInterruptDuration(samples int, wait bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if samples < 0 || samples > maxInterruptDuration {
+		return fmt.Errorf("interrupt duration %d is out of range 0..%d", samples, maxInterruptDuration)
+	}
+	durationBits := byte(samples)
+	if wait {
+		durationBits |= 1 << 7
+	}
+	return g.bus.WriteByteToReg(g.address, regInt1Duration, durationBits)
+}
+
+// ThresholdEvents is a decoded INT1_SRC register.
+type ThresholdEvents struct {
+	// Active tells that the interrupt has fired (one or more events have occurred).
+	Active bool
+	// XHigh, XLow, YHigh, YLow, ZHigh and ZLow tell which events have occurred.
+	XHigh, XLow, YHigh, YLow, ZHigh, ZLow bool
+}
+
+func decodeThresholdEvents(src byte) ThresholdEvents {
+	return ThresholdEvents{
+		Active: src&(1<<6) != 0,
+		XLow:   src&(1<<0) != 0,
+		XHigh:  src&(1<<1) != 0,
+		YLow:   src&(1<<2) != 0,
+		YHigh:  src&(1<<3) != 0,
+		ZLow:   src&(1<<4) != 0,
+		ZHigh:  src&(1<<5) != 0,
+	}
+}
+
+// ThresholdInterruptSource reads which threshold interrupt events have occurred.
+// Reading it clears a latched interrupt.
+func (g *Gyro) ThresholdInterruptSource() (ThresholdEvents, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	src, e := g.bus.ReadByteFromReg(g.address, regInt1Src)
+	return decodeThresholdEvents(src), e
+}
+
+// InterruptStatus tells why an interrupt pin has been asserted.
+type InterruptStatus struct {
+	// Threshold is a decoded INT1_SRC register (threshold interrupt on INT1 pin).
+	Threshold ThresholdEvents
+	// FIFO is a decoded FIFO_SRC register (FIFO interrupts on DRDY/INT2 pin).
+	FIFO FIFOStatus
+}
+
+// InterruptStatus reads INT1_SRC and FIFO_SRC registers in a single transaction.
+// NOTE: reading INT1_SRC always clears a latched threshold interrupt, so the events are only
+// reported by one call to InterruptStatus() or ThresholdInterruptSource().
+func (g *Gyro) InterruptStatus() (InterruptStatus, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// FIFO_SRC, INT1_CFG, INT1_SRC.
+	data := make([]byte, regInt1Src-regFifoSrc+1)
+	// Set MSB for the slave to advance the register on every read.
+	if e := minimu9.ReadFullSliceFromReg(g.bus, g.address, regFifoSrc|(1<<7), data); e != nil {
+		return InterruptStatus{}, e
+	}
+	return InterruptStatus{
+		Threshold: decodeThresholdEvents(data[regInt1Src-regFifoSrc]),
+		FIFO:      decodeFIFOStatus(data[0]),
+	}, nil
+}
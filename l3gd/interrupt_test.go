@@ -0,0 +1,25 @@
+package l3gd
+
+import (
+	"testing"
+)
+
+func TestConfigureInterruptPinsActiveLow(t *testing.T) {
+	bus := newTestBus()
+	g := NewGyro(bus, testAddress)
+	if e := g.ConfigureInterruptPins(InterruptConfig{ActiveLow: true, OpenDrain: true}); e != nil {
+		t.Fatal(e)
+	}
+	if ctrl3 := bus.Register(testAddress, regCtrl3); ctrl3&0x30 != 0x30 {
+		t.Errorf("expected H_Lactive and PP_OD set in CTRL3, got 0x%02X", ctrl3)
+	}
+	if lowOdr := bus.Register(testAddress, regLowOdr); lowOdr&(1<<5) == 0 {
+		t.Errorf("expected DRDY_HL set in LOW_ODR, got 0x%02X", lowOdr)
+	}
+	if e := g.ConfigureInterruptPins(InterruptConfig{}); e != nil {
+		t.Fatal(e)
+	}
+	if lowOdr := bus.Register(testAddress, regLowOdr); lowOdr&(1<<5) != 0 {
+		t.Errorf("expected DRDY_HL cleared in LOW_ODR, got 0x%02X", lowOdr)
+	}
+}
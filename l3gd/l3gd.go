@@ -1,9 +1,13 @@
 package l3gd
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"math"
+	"sync"
+	"time"
 
-	"github.com/dasfoo/i2c"
 	"github.com/dasfoo/minimu9"
 	"github.com/golang/geo/r3"
 )
@@ -11,53 +15,411 @@ import (
 // Gyro is a sensor driver implementation for L3GD20H Gyro.
 // Documentation: http://goo.gl/Nb95rx
 // Arduino code samples: https://github.com/pololu/l3g-arduino
+// Gyro methods are safe for concurrent use; sharing the bus with other devices safely
+// is up to the caller though.
 type Gyro struct {
-	bus            i2c.Bus
+	mu sync.Mutex
+	// streamMu is held for reading by ReadStream() and Poll() around every read, and for writing
+	// by Reconfigure().
+	streamMu sync.RWMutex
+	// bus is either directBus or a wrapper around it, see updateBus().
+	bus            minimu9.Bus
+	directBus      minimu9.Bus
+	retries        int
+	backoff        time.Duration
+	singleByte     bool
 	address        byte
 	fullScaleIndex byte
 	frequency      float64
-	Offset         r3.Vector
+	axesBits       byte
+	byteOrder      binary.ByteOrder
+	axisMap        axisMap
+	frame          Frame
+	autoRange      bool
+	autoRangeCalm  int
+	tempBias       *TempCompensatedBias
+	scaleTrim      r3.Vector
+	fifoReadTime   time.Time
+	fifoRecover    bool
+	readBuffer     [6]byte
+	fifoBuffer     [fifoSize * 6]byte
+	accelPrevious  r3.Vector
+	accelTime      time.Time
+	spi            bool
+	logger         minimu9.Logger
+	oversampling   int
+	streamTimeout  int
+	streamReset    bool
+	consistency    int
+	applied        []configRegister
+	// closed is closed by Close() to stop background reading.
+	closed chan struct{}
+	// Offset is subtracted from every reading, in degrees/s. Use SetBias() and Bias()
+	// instead of accessing it directly when Gyro is used concurrently.
+	Offset r3.Vector
 }
 
-// DefaultAddress is a default I2C address for this sensor.
-const DefaultAddress = 0x6b
+const (
+	// DefaultAddress is a default I2C address for this sensor (SDO/SA0 pin pulled high).
+	DefaultAddress = 0x6b
+	// AlternateAddress is an I2C address for this sensor when SDO/SA0 pin is tied low.
+	AlternateAddress = 0x6a
+)
 
 // NewGyro creates new instance bound to I2C bus and address.
-func NewGyro(bus i2c.Bus, addr byte) *Gyro {
+func NewGyro(bus minimu9.Bus, addr byte) *Gyro {
 	return &Gyro{
 		bus:            bus,
+		directBus:      bus,
 		address:        addr,
 		fullScaleIndex: 0,
 		frequency:      12.5,
+		axesBits:       0x07,
+		byteOrder:      binary.LittleEndian,
+		axisMap:        identityAxisMap,
+		scaleTrim:      unityScaleTrim,
+		consistency:    defaultConsistencyThreshold,
+		closed:         make(chan struct{}),
+	}
+}
+
+// NewGyroSPI creates new instance talking to the sensor over SPI, which allows for faster
+// transfers than I2C.
+func NewGyroSPI(dev minimu9.SPIDevice) *Gyro {
+	g := NewGyro(minimu9.NewSPIBus(dev), 0)
+	g.spi = true
+	return g
+}
+
+// NewGyroValidated creates new instance bound to I2C bus and address, after validating that
+// the address is either DefaultAddress or AlternateAddress. Unlike NewGyroChecked(), it does
+// not talk to the bus, it only catches mistakes like passing an 8-bit (shifted) address.
+func NewGyroValidated(bus minimu9.Bus, addr byte) (*Gyro, error) {
+	if addr != DefaultAddress && addr != AlternateAddress {
+		if addr>>1 == DefaultAddress || addr>>1 == AlternateAddress {
+			return nil, fmt.Errorf("address 0x%02X looks like an 8-bit address, use 0x%02X",
+				addr, addr>>1)
+		}
+		return nil, fmt.Errorf("address 0x%02X is neither 0x%02X nor 0x%02X",
+			addr, DefaultAddress, AlternateAddress)
+	}
+	return NewGyro(bus, addr), nil
+}
+
+// NewGyroChecked creates new instance bound to I2C bus and address, and verifies
+// with Check() that the device responding at that address is an L3GD20H.
+func NewGyroChecked(bus minimu9.Bus, addr byte) (*Gyro, error) {
+	g := NewGyro(bus, addr)
+	if e := g.Check(); e != nil {
+		return nil, e
 	}
+	return g, nil
+}
+
+// Detect probes both DefaultAddress and AlternateAddress on the bus with Check(), and returns
+// instances for the addresses where an L3GD20H responds. If none does, the last error is returned.
+func Detect(bus minimu9.Bus) ([]*Gyro, error) {
+	var (
+		gyros     []*Gyro
+		lastError error
+	)
+	for _, addr := range []byte{DefaultAddress, AlternateAddress} {
+		if g, e := NewGyroChecked(bus, addr); e == nil {
+			gyros = append(gyros, g)
+		} else {
+			lastError = e
+		}
+	}
+	if len(gyros) == 0 {
+		return nil, lastError
+	}
+	return gyros, nil
 }
 
 const (
-	regCtrl1  = 0x20
-	regCtrl4  = 0x23
-	regLowOdr = 0x39
+	regWhoAmI  = 0x0f
+	regCtrl1   = 0x20
+	regCtrl4   = 0x23
+	regCtrl5   = 0x24
+	regOutTemp = 0x26
+	regStatus  = 0x27
+	regOutX    = 0x28
+	regLowOdr  = 0x39
 )
 
+// SetRetries makes the driver retry failed bus operations up to the specified number of times,
+// sleeping for backoff before each retry. Only bus errors are retried, not warnings like
+// minimu9.DataAvailabilityError. There are no retries by default.
+func (g *Gyro) SetRetries(retries int, backoff time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.retries, g.backoff = retries, backoff
+	g.updateBus()
+}
+
+// SetBurstTransfers enables (default) or disables multi-byte transfers with register address
+// auto-increment. When disabled, every register is transferred separately, which is slower, but
+// works with buses or multiplexers which don't support auto-increment (see minimu9.Bus).
+func (g *Gyro) SetBurstTransfers(enable bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.singleByte = !enable
+	g.updateBus()
+}
+
+// SetLogger makes the driver log debug messages to logger: register writes, failed bus operations
+// (including every retry), data "freshness" warnings and FIFO overruns. Use nil (the default)
+// to turn logging off, which has no overhead.
+func (g *Gyro) SetLogger(logger minimu9.Logger) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.logger = logger
+	g.updateBus()
+}
+
+// logf logs a message with the logger set by SetLogger(), if any.
+func (g *Gyro) logf(format string, v ...interface{}) {
+	if g.logger != nil {
+		g.logger.Printf(format, v...)
+	}
+}
+
+// updateBus wraps directBus according to the bus settings.
+func (g *Gyro) updateBus() {
+	g.bus = g.directBus
+	if g.logger != nil {
+		g.bus = minimu9.NewLoggingBus(g.bus, g.logger)
+	}
+	if g.retries > 0 {
+		g.bus = minimu9.NewRetryingBus(g.bus, g.retries, g.backoff)
+	}
+	if g.singleByte {
+		g.bus = minimu9.NewSingleByteBus(g.bus)
+	}
+}
+
+// whoAmI is the WHO_AM_I register value identifying L3GD20H.
+const whoAmI = 0xd7
+
+// Check reads WHO_AM_I register to verify that the device is an L3GD20H.
+// This catches wiring mistakes where a different sensor answers at the same address.
+func (g *Gyro) Check() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	id, e := g.bus.ReadByteFromReg(g.address, regWhoAmI)
+	if e != nil {
+		return e
+	}
+	if id != whoAmI {
+		return fmt.Errorf("unexpected WHO_AM_I 0x%02X, not an L3GD20H", id)
+	}
+	return nil
+}
+
 // Sleep puts the sensor in low power consumption mode.
+// See SetPowerMode() for a sleep mode which wakes up faster.
 func (g *Gyro) Sleep() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	// We are actually setting it to power-down mode rather than sleep.
 	// Power-down consumes less power, but takes longer to wake.
-	return g.bus.WriteByteToReg(g.address, regCtrl1, 0x00)
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl1, 1<<3, 0)
+}
+
+// frequencies are supported output data rates, in Hz. The first 3 use low ODR mode.
+var frequencies = []float64{12.5, 25, 50, 100, 200, 400, 800}
+
+// frequencyIndex returns index of the data rate in frequencies that will be chosen for value,
+// which is clamped to the supported range.
+func frequencyIndex(value float64) int {
+	return int(math.Min(math.Max(math.Log2(value/12.5), 0), float64(len(frequencies)-1)))
 }
 
-// SetFrequency sets gyro output data rate, in Hz. Values: 12.5 .. 800.
-func (g *Gyro) SetFrequency(value float64) error {
-	g.frequency = value
-	frequencyBits := byte(math.Log2(value / 12.5))
+// SetFrequency sets gyro output data rate, in Hz. Values: 12.5, 25, 50, 100, 200, 400, 800.
+// Other values within 12.5 .. 800 are rounded down to a supported rate, and values outside of
+// it are rejected with an error. Returns the rate actually set.
+func (g *Gyro) SetFrequency(value float64) (float64, error) {
+	if !(value >= frequencies[0] && value <= frequencies[len(frequencies)-1]) {
+		return 0, fmt.Errorf("data rate %v Hz is out of range %v .. %v Hz",
+			value, frequencies[0], frequencies[len(frequencies)-1])
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.setFrequency(value)
+}
+
+func (g *Gyro) setFrequency(value float64) (float64, error) {
+	index := frequencyIndex(value)
+	frequencyBits := byte(index)
 	var lowOdr = byte(1)
 	if frequencyBits > 2 {
 		frequencyBits -= 3
 		lowOdr = 0
 	}
 	if e := minimu9.WriteBitsToReg(g.bus, g.address, regLowOdr, 1, lowOdr); e != nil {
+		return 0, e
+	}
+	// Set the data rate and turn on normal mode with the enabled axes (leaving sleep mode),
+	// keeping the bandwidth.
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl1,
+		0xcf, (1<<3)|frequencyBits<<6|g.axesBits); e != nil {
+		return 0, e
+	}
+	g.frequency = frequencies[index]
+	return g.frequency, nil
+}
+
+// Frequency reads the output data rate currently set in the sensor, in Hz.
+func (g *Gyro) Frequency() (float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	lowOdr, e := g.bus.ReadByteFromReg(g.address, regLowOdr)
+	if e != nil {
+		return 0, e
+	}
+	ctrl1, e := g.bus.ReadByteFromReg(g.address, regCtrl1)
+	if e != nil {
+		return 0, e
+	}
+	return frequencies[decodeFrequencyIndex(ctrl1, lowOdr)], nil
+}
+
+// SetLowODRMode switches low ODR mode on or off, keeping the DR bits. In low ODR mode, the DR
+// bits select 12.5, 25 or 50 Hz data rate; otherwise they select 100, 200, 400 or 800 Hz.
+// SetFrequency() switches the mode as needed, so this is rarely necessary; use Frequency()
+// to find out the data rate resulting from the switch.
+func (g *Gyro) SetLowODRMode(enable bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var lowOdr byte
+	if enable {
+		lowOdr = 1
+	}
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regLowOdr, 1, lowOdr); e != nil {
+		return e
+	}
+	ctrl1, e := g.bus.ReadByteFromReg(g.address, regCtrl1)
+	if e != nil {
 		return e
 	}
-	return g.bus.WriteByteToReg(g.address, regCtrl1, 0x0F|frequencyBits<<6)
+	g.frequency = frequencies[decodeFrequencyIndex(ctrl1, lowOdr)]
+	return nil
+}
+
+// LowODRMode reads whether low ODR mode is on, i.e. data rate is 50 Hz or lower.
+func (g *Gyro) LowODRMode() (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	lowOdr, e := g.bus.ReadByteFromReg(g.address, regLowOdr)
+	return lowOdr&1 != 0, e
+}
+
+// DisableI2C turns off the I2C interface of the sensor, so that glitches on the I2C bus can't
+// disturb it while it's used over SPI. Once disabled, I2C stays off until Reset() (through SPI)
+// or a power cycle. It is refused for Gyro created with NewGyro(), which would lose the sensor.
+func (g *Gyro) DisableI2C() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.spi {
+		return errors.New("refusing to disable I2C while talking to the sensor over I2C")
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regLowOdr, 1<<3, 1<<3)
+}
+
+// decodeFrequencyIndex returns index in frequencies of the data rate set in the registers.
+func decodeFrequencyIndex(ctrl1, lowOdr byte) int {
+	frequencyBits := int(ctrl1 >> 6)
+	if lowOdr&1 == 0 {
+		return frequencyBits + 3
+	}
+	// Both 10 and 11 select 50Hz in low ODR mode.
+	return int(math.Min(float64(frequencyBits), 2))
+}
+
+// SetAxesEnabled enables or disables measurement on individual axes.
+// All axes are enabled by default. Disabled axes can still be read, but return stale data.
+func (g *Gyro) SetAxesEnabled(x, y, z bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var axesBits byte
+	if x {
+		axesBits |= 1 << 0
+	}
+	if y {
+		axesBits |= 1 << 1
+	}
+	if z {
+		axesBits |= 1 << 2
+	}
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl1, 0x07, axesBits); e != nil {
+		return e
+	}
+	g.axesBits = axesBits
+	return nil
+}
+
+// AxesEnabled reads which axes are enabled in the sensor, e.g. to find out why an axis reads
+// zero. All axes are disabled in sleep mode. See also Config().
+func (g *Gyro) AxesEnabled() (x, y, z bool, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ctrl1, e := g.bus.ReadByteFromReg(g.address, regCtrl1)
+	return ctrl1&(1<<0) != 0, ctrl1&(1<<1) != 0, ctrl1&(1<<2) != 0, e
+}
+
+// PowerMode is a sensor power mode, see SetPowerMode().
+type PowerMode byte
+
+const (
+	// PowerModePowerDown has the lowest current consumption, but takes the longest to wake.
+	// This is the mode set by Sleep().
+	PowerModePowerDown PowerMode = iota
+	// PowerModeSleep disables all axes while keeping the sensor powered, so that it consumes
+	// more current than in power-down mode, but wakes up much faster.
+	PowerModeSleep
+	// PowerModeNormal is the measurement mode, with axes enabled by SetAxesEnabled().
+	PowerModeNormal
+)
+
+// typicalCurrent is a typical supply current per PowerMode, in µA.
+var typicalCurrent = []float64{1, 2500, 5000}
+
+// EstimatedCurrent returns typical supply current, in µA, in the power mode the sensor is in.
+// The datasheet specifies it for each power mode only, irrespective of the data rate.
+func (g *Gyro) EstimatedCurrent() (float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ctrl1, e := g.bus.ReadByteFromReg(g.address, regCtrl1)
+	if e != nil {
+		return 0, e
+	}
+	mode := PowerModeNormal
+	if ctrl1&(1<<3) == 0 {
+		mode = PowerModePowerDown
+	} else if ctrl1&0x07 == 0 {
+		mode = PowerModeSleep
+	}
+	return typicalCurrent[mode], nil
+}
+
+// SetPowerMode switches the sensor between power-down, sleep and normal modes.
+// Switching to PowerModeNormal is the same as Wake(), except that the data rate is not written;
+// Wake() and SetFrequency() also switch to normal mode from either of the other modes.
+func (g *Gyro) SetPowerMode(mode PowerMode) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var modeBits byte
+	switch mode {
+	case PowerModePowerDown:
+		modeBits = g.axesBits
+	case PowerModeSleep:
+		modeBits = 1 << 3
+	case PowerModeNormal:
+		modeBits = (1 << 3) | g.axesBits
+	default:
+		return fmt.Errorf("unsupported power mode %d", mode)
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl1, 0x0f, modeBits)
 }
 
 var (
@@ -65,22 +427,91 @@ var (
 	scaleRatio = []float64{0.00875, 0.0175, 0.07}
 )
 
-// SetFullScale sets gyro full scale, which affects sensitivity. Values: 245, 500, 2000 (degrees/s)
+// SetFullScale sets gyro full scale, which affects sensitivity. Values: 245, 500, 2000 (degrees/s).
+// Other values are rejected with an error. Only the FS bits of CTRL4 are modified.
 func (g *Gyro) SetFullScale(value float64) error {
-	g.fullScaleIndex = byte(len(scaleBits) - 1)
-	for index, maxScale := range scaleBits {
-		if maxScale >= value {
-			g.fullScaleIndex = byte(index)
-			break
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for index, scale := range scaleBits {
+		if scale == value {
+			g.autoRangeCalm = 0
+			return g.setFullScaleIndex(index)
 		}
 	}
-	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl4,
-		(1<<4)|(1<<5), g.fullScaleIndex<<4)
+	return fmt.Errorf("unsupported full scale %v degrees/s, must be one of %v", value, scaleBits)
 }
 
-// Wake enables sensor if it was put into power-down mode with Sleep().
+// Sensitivity returns the ratio used to convert raw counts into angular speed, in millidegrees
+// per second per count, for the full scale last set by SetFullScale(): 8.75, 17.5 or 70.
+func (g *Gyro) Sensitivity() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return scaleRatio[g.fullScaleIndex] * 1000
+}
+
+// DPSToCounts converts angular speed, in degrees per second, into raw counts (rounded to the
+// nearest) at the full scale last set by SetFullScale(), e.g. for interrupt thresholds.
+func (g *Gyro) DPSToCounts(dps float64) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.dpsToCounts(dps)
+}
+
+func (g *Gyro) dpsToCounts(dps float64) int {
+	return int(math.Floor(dps/scaleRatio[g.fullScaleIndex] + 0.5))
+}
+
+// CountsToDPS converts raw counts into angular speed, in degrees per second, at the full scale
+// last set by SetFullScale(). Offset and other corrections are not applied.
+func (g *Gyro) CountsToDPS(counts int) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.countsToDPS(counts)
+}
+
+func (g *Gyro) countsToDPS(counts int) float64 {
+	return float64(counts) * scaleRatio[g.fullScaleIndex]
+}
+
+// SetBlockDataUpdate enables or disables block data update (BDU). When enabled, output registers
+// are not updated until both high and low bytes of each axis are read, so they never come from
+// different samples. Read() fetches all axes in a single burst, which makes such tearing unlikely
+// but still possible at high data rates; BDU rules it out, and is a must for partial reads.
+func (g *Gyro) SetBlockDataUpdate(enable bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var bduBit byte
+	if enable {
+		bduBit = 1 << 7
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl4, 1<<7, bduBit)
+}
+
+// SetBigEndian selects byte order of the output registers (BLE): big endian (MSB at lower
+// address) or little endian, which is the default. Reads decode data accordingly.
+func (g *Gyro) SetBigEndian(bigEndian bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var bleBit byte
+	var order binary.ByteOrder = binary.LittleEndian
+	if bigEndian {
+		bleBit = 1 << 6
+		order = binary.BigEndian
+	}
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl4, 1<<6, bleBit); e != nil {
+		return e
+	}
+	g.byteOrder = order
+	return nil
+}
+
+// Wake enables sensor if it was put into power-down mode with Sleep(), or into sleep mode with
+// SetPowerMode().
 func (g *Gyro) Wake() error {
-	return g.SetFrequency(g.frequency)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, e := g.setFrequency(g.frequency)
+	return e
 }
 
 // Calibrate measures gyro offset until stop channel is written to.
@@ -88,18 +519,269 @@ func (g *Gyro) Wake() error {
 // NOTE: during calibration, the sensor has to be static (not moving).
 func (g *Gyro) Calibrate(stop chan int) error {
 	ioffset, _, e := minimu9.GetOffsetAndRange(
-		func() (minimu9.IntVector, error) { return minimu9.ReadVector(g.bus, g.address, 0x28) },
+		func() (minimu9.IntVector, error) {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			return minimu9.ReadVectorWithByteOrder(g.bus, g.address, regOutX, g.byteOrder)
+		},
 		stop)
-	offset := ioffset.R3().Mul(scaleRatio[g.fullScaleIndex])
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	if e == nil {
-		g.Offset = offset
+		g.Offset = ioffset.R3().Mul(scaleRatio[g.fullScaleIndex])
 	}
 	return e
 }
 
-// Read reads angular speed data from the sensor, in degrees per second.
+// ReadDPS reads angular speed data from the sensor, in degrees per second.
+// Raw counts are converted using the sensitivity of the full scale last set by SetFullScale(),
+// or selected automatically, see EnableAutoRange(). Offset may be set automatically too, see
+// SetTempCompensatedBias(). See also SetOversampling().
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (g *Gyro) ReadDPS() (r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e := g.updateTempBias(); e != nil {
+		return r3.Vector{}, e
+	}
+	var v r3.Vector
+	var e error
+	if g.oversampling > 1 {
+		v, e = g.average(g.oversampling)
+	} else {
+		v, e = g.readScaled()
+	}
+	if g.autoRange && (e == nil || isDataAvailabilityError(e) && !isDataNotAvailable(e)) {
+		if rangeErr := g.adjustRange(v); rangeErr != nil {
+			return r3.Vector{}, rangeErr
+		}
+	}
+	return g.output(v), e
+}
+
+// ReadRadPS reads angular speed data from the sensor, in radians per second.
+// Offset is subtracted before converting, same as in ReadDPS().
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (g *Gyro) ReadRadPS() (r3.Vector, error) {
+	v, e := g.ReadDPS()
+	return v.Mul(math.Pi / 180), e
+}
+
+// ReadWithStatus reads a single sample, same as Read() without oversampling (see
+// SetOversampling()), and also returns decoded STATUS register, which is read in the same pass
+// anyway.
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (g *Gyro) ReadWithStatus() (r3.Vector, StatusFlags, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e := g.updateTempBias(); e != nil {
+		return r3.Vector{}, StatusFlags{}, e
+	}
+	v, status, e := g.readScaledWithStatus()
+	return g.output(v), decodeStatus(status), e
+}
+
+// ReadTimed reads a single sample, same as Read() without oversampling (see SetOversampling()),
+// and also returns the time when the read has completed, so that the interval between samples
+// can be computed accurately despite scheduling jitter.
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (g *Gyro) ReadTimed() (r3.Vector, time.Time, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.readTimed()
+}
+
+func (g *Gyro) readTimed() (r3.Vector, time.Time, error) {
+	if e := g.updateTempBias(); e != nil {
+		return r3.Vector{}, time.Time{}, e
+	}
+	v, e := g.readScaled()
+	t := time.Now()
+	return g.output(v), t, e
+}
+
+// readScaled reads angular speed in degrees per second, without subtracting Offset.
+func (g *Gyro) readScaled() (r3.Vector, error) {
+	v, _, e := g.readScaledWithStatus()
+	return v, e
+}
+
+// readScaledWithStatus is the same as readScaled, but also returns STATUS register value.
+func (g *Gyro) readScaledWithStatus() (r3.Vector, byte, error) {
+	v, status, e := g.readRawWithStatus()
+	return v.R3().Mul(scaleRatio[g.fullScaleIndex]), status, e
+}
+
+// readRawWithStatus reads STATUS register and raw counts. It reuses readBuffer, so that reading
+// does not allocate unless there's an error or a warning.
+func (g *Gyro) readRawWithStatus() (minimu9.IntVector, byte, error) {
+	status, e := g.bus.ReadByteFromReg(g.address, regStatus)
+	if e != nil {
+		return minimu9.IntVector{}, status, e
+	}
+	// Set MSB for the slave to advance the register on every read.
+	if e = minimu9.ReadFullSliceFromReg(g.bus, g.address, regOutX|(1<<7),
+		g.readBuffer[:]); e != nil {
+		return minimu9.IntVector{}, status, e
+	}
+	e = minimu9.StatusError(status)
+	if e != nil {
+		g.logf("STATUS 0x%02X: %v", status, e)
+	}
+	return minimu9.DecodeVector(g.readBuffer[:], g.byteOrder), status, e
+}
+
+// saturationCounts is the absolute raw value from which a measurement is considered saturated.
+const saturationCounts = math.MaxInt16 * 99 / 100
+
+// ReadDPSChecked is the same as ReadDPS(), but also checks whether the measurement is within
+// 1% of the sensor range limit on any axis, and returns minimu9.SaturationError (with sensor axes,
+// regardless of SetAxisMap) along with the value if so, because the actual rate may be beyond it.
+// SaturationError takes precedence over minimu9.DataAvailabilityError warnings.
+func (g *Gyro) ReadDPSChecked() (r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e := g.updateTempBias(); e != nil {
+		return r3.Vector{}, e
+	}
+	raw, _, e := g.readRawWithStatus()
+	if e != nil && !isDataAvailabilityError(e) {
+		return r3.Vector{}, e
+	}
+	saturation := minimu9.SaturationError{
+		X: math.Abs(float64(raw.X)) >= saturationCounts,
+		Y: math.Abs(float64(raw.Y)) >= saturationCounts,
+		Z: math.Abs(float64(raw.Z)) >= saturationCounts,
+	}
+	if saturation.X || saturation.Y || saturation.Z {
+		e = &saturation
+	}
+	return g.output(raw.R3().Mul(scaleRatio[g.fullScaleIndex])), e
+}
+
+// Read reads angular speed data from the sensor, in degrees per second. Same as ReadDPS().
 // Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
 func (g *Gyro) Read() (r3.Vector, error) {
-	v, e := minimu9.ReadStatusAndVector(g.bus, g.address, 0x27)
-	return v.Mul(scaleRatio[g.fullScaleIndex]).Sub(g.Offset), e
+	return g.ReadDPS()
+}
+
+// ReadRaw reads the output registers, OUT_X_L .. OUT_Z_H, verbatim, without decoding them or
+// checking data "freshness", e.g. for bit-exact logging or debugging byte order issues.
+func (g *Gyro) ReadRaw() ([6]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var data [6]byte
+	// Set MSB for the slave to advance the register on every read.
+	e := minimu9.ReadFullSliceFromReg(g.bus, g.address, regOutX|(1<<7), data[:])
+	return data, e
+}
+
+// ReadTemperature reads the temperature sensor, in degrees Celsius relative to an uncalibrated
+// reference point (i.e. 0 is not 0°C, and the reference differs between chips).
+// The sensor output changes by -1 LSB/°C; the sign is inverted here so that the returned value
+// grows with temperature.
+func (g *Gyro) ReadTemperature() (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	t, e := g.bus.ReadByteFromReg(g.address, regOutTemp)
+	return decodeTemperature(t), e
+}
+
+func decodeTemperature(outTemp byte) int {
+	return -int(int8(outTemp))
+}
+
+// ReadTemperatureC reads the temperature sensor, in degrees Celsius, given referenceC, the
+// temperature at which ReadTemperature() returns 0. It's a one-point calibration: to find
+// referenceC, subtract ReadTemperature() from the temperature known at some moment (e.g. measured
+// with a thermometer). The slope is roughly 1 LSB/°C per the datasheet, so the result tracks the
+// real temperature well enough for drift compensation, but it's not an accurate thermometer.
+func (g *Gyro) ReadTemperatureC(referenceC float64) (float64, error) {
+	t, e := g.ReadTemperature()
+	return referenceC + float64(t), e
+}
+
+// selfClearTimeout is how long Reboot() and Reset() wait for the BOOT and SW_RESET bits
+// to self-clear.
+const selfClearTimeout = 10 * time.Millisecond
+
+// Reboot reloads trimming parameters from the internal flash and waits for it to complete.
+// This is the recommended recovery path when the sensor gets into a bad state.
+func (g *Gyro) Reboot() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl5, 1<<7, 1<<7); e != nil {
+		return e
+	}
+	return g.waitSelfClear(regCtrl5, 1<<7, "reboot")
+}
+
+// waitSelfClear waits for the bit in reg to be cleared by the sensor, up to selfClearTimeout.
+func (g *Gyro) waitSelfClear(reg, bit byte, operation string) error {
+	deadline := time.Now().Add(selfClearTimeout)
+	for {
+		value, e := g.bus.ReadByteFromReg(g.address, reg)
+		if e != nil {
+			return e
+		}
+		if value&bit == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for the sensor to %s", operation)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Reset restores configuration registers to their power-on values using software reset
+// (SW_RESET bit), which also puts the sensor into power-down mode, and resets full scale,
+// byte order, axis map, frame, automatic range selection, oversampling, scale trim and bias
+// (Offset and temperature compensation) as in NewGyro(). It waits for the reset to complete.
+// Unlike Reboot(), it does not reload trimming parameters.
+func (g *Gyro) Reset() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e := g.bus.WriteByteToReg(g.address, regLowOdr, 1<<2); e != nil {
+		return e
+	}
+	if e := g.waitSelfClear(regLowOdr, 1<<2, "reset"); e != nil {
+		return e
+	}
+	g.fullScaleIndex = 0
+	g.frequency = 12.5
+	g.axesBits = 0x07
+	g.byteOrder = binary.LittleEndian
+	g.axisMap = identityAxisMap
+	g.frame = FrameSensor
+	g.scaleTrim = unityScaleTrim
+	g.autoRange = false
+	g.oversampling = 0
+	g.tempBias = nil
+	g.Offset = r3.Vector{}
+	return nil
+}
+
+// ErrClosed is returned by Poll() and Recorder when Gyro has been closed.
+var ErrClosed = errors.New("gyro has been closed")
+
+// Close stops background reading started with ReadStream() or Poll(), disables interrupts and
+// puts the sensor into power-down mode. Subsequent calls do nothing.
+func (g *Gyro) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	select {
+	case <-g.closed:
+		return nil
+	default:
+	}
+	close(g.closed)
+	// Keep the pin configuration (H_Lactive, PP_OD), so that the pins stay deasserted.
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl3, 0xcf, 0x00); e != nil {
+		return e
+	}
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regInt1Cfg, 0x3f, 0x00); e != nil {
+		return e
+	}
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl1, 1<<3, 0)
 }
@@ -0,0 +1,99 @@
+package l3gd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/dasfoo/minimu9/minimu9test"
+	"github.com/golang/geo/r3"
+)
+
+const testAddress = 0x6b
+
+// shortBus is a fake bus which delivers 2 bytes fewer than requested by slice reads.
+type shortBus struct {
+	*minimu9test.Bus
+}
+
+func (b shortBus) ReadSliceFromReg(addr, reg byte, data []byte) (int, error) {
+	if len(data) < 2 {
+		return 0, nil
+	}
+	return b.Bus.ReadSliceFromReg(addr, reg, data[:len(data)-2])
+}
+
+// newTestBus returns a fake bus with new data available and non-zero output registers.
+func newTestBus() *minimu9test.Bus {
+	bus := minimu9test.NewBus()
+	bus.SetRegister(testAddress, regStatus, 0x0f)
+	for reg := byte(regOutX); reg < regOutX+6; reg++ {
+		bus.SetRegister(testAddress, reg, 0x11)
+	}
+	return bus
+}
+
+func assertShortRead(t *testing.T, method string, e error) {
+	if _, ok := e.(*minimu9.ShortReadError); !ok {
+		t.Errorf("%s: expected *minimu9.ShortReadError, got %#v", method, e)
+	}
+}
+
+func TestShortRead(t *testing.T) {
+	bus := newTestBus()
+	// 2 samples stored in FIFO.
+	bus.SetRegister(testAddress, regFifoSrc, 0x02)
+	g := NewGyro(shortBus{bus}, testAddress)
+
+	v, e := g.Read()
+	assertShortRead(t, "Read()", e)
+	if e != nil && !strings.Contains(e.Error(), "expected 6 bytes, got 4") {
+		t.Errorf("Read(): expected the error to describe the short read, got %q", e)
+	}
+	if v != (r3.Vector{}) {
+		t.Errorf("Read(): expected no data decoded, got %v", v)
+	}
+
+	s, e := g.ReadAll()
+	assertShortRead(t, "ReadAll()", e)
+	if s != (Sample{}) {
+		t.Errorf("ReadAll(): expected no data decoded, got %+v", s)
+	}
+
+	samples, e := g.ReadFIFO()
+	assertShortRead(t, "ReadFIFO()", e)
+	if len(samples) != 0 {
+		t.Errorf("ReadFIFO(): expected no data decoded, got %v", samples)
+	}
+
+	for _, axis := range []Axis{AxisX, AxisY, AxisZ} {
+		value, e := g.ReadAxis(axis)
+		assertShortRead(t, "ReadAxis()", e)
+		if value != 0 {
+			t.Errorf("ReadAxis(%d): expected no data decoded, got %v", axis, value)
+		}
+	}
+}
+
+func BenchmarkReadDPS(b *testing.B) {
+	g := NewGyro(newTestBus(), testAddress)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, e := g.ReadDPS(); e != nil {
+			b.Fatal(e)
+		}
+	}
+}
+
+func TestReadDPSDoesNotAllocate(t *testing.T) {
+	g := NewGyro(newTestBus(), testAddress)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, e := g.ReadDPS(); e != nil {
+			t.Fatal(e)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations per ReadDPS(), got %v", allocs)
+	}
+}
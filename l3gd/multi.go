@@ -0,0 +1,72 @@
+package l3gd
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+)
+
+// MultiReader reads several gyros as close in time as possible, e.g. to average redundant
+// sensors or for differential sensing.
+type MultiReader struct {
+	gyros      []*Gyro
+	concurrent bool
+}
+
+// Reading is a sample read by MultiReader from one of the gyros.
+type Reading struct {
+	// DPS is angular speed, as returned by ReadTimed().
+	DPS r3.Vector
+	// Time is when the read has completed, as returned by ReadTimed().
+	Time time.Time
+	// Err is the error returned by ReadTimed(), which may be a data "freshness" warning.
+	Err error
+}
+
+// NewMultiReader creates a new reader of the gyros, which are read sequentially by default.
+func NewMultiReader(gyros ...*Gyro) *MultiReader {
+	return &MultiReader{gyros: gyros}
+}
+
+// SetConcurrent makes ReadAll() read all gyros concurrently, which gives better synchronized
+// samples, but is only safe if each gyro is on a separate bus.
+func (m *MultiReader) SetConcurrent(concurrent bool) {
+	m.concurrent = concurrent
+}
+
+// ReadAll reads every gyro once and returns the readings in the order of gyros passed to
+// NewMultiReader(). A failure of some gyros does not affect the others: see Err of each reading.
+// The returned error is only set if all gyros have failed (not counting warnings).
+func (m *MultiReader) ReadAll() ([]Reading, error) {
+	readings := make([]Reading, len(m.gyros))
+	read := func(i int) {
+		r := &readings[i]
+		r.DPS, r.Time, r.Err = m.gyros[i].ReadTimed()
+	}
+	if m.concurrent {
+		var wg sync.WaitGroup
+		for i := range m.gyros {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				read(i)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range m.gyros {
+			read(i)
+		}
+	}
+	for _, r := range readings {
+		if r.Err == nil || isDataAvailabilityError(r.Err) {
+			return readings, nil
+		}
+	}
+	if len(readings) == 0 {
+		return readings, nil
+	}
+	return readings, errors.New("all gyros have failed to read")
+}
@@ -0,0 +1,72 @@
+package l3gd
+
+import (
+	"time"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+// Option configures Gyro created by NewGyroWithOptions().
+type Option func(g *Gyro) error
+
+// NewGyroWithOptions creates new instance bound to I2C bus and address, and applies options
+// in the order they are specified. The first error is returned, if any.
+func NewGyroWithOptions(bus minimu9.Bus, addr byte, options ...Option) (*Gyro, error) {
+	g := NewGyro(bus, addr)
+	for _, option := range options {
+		if e := option(g); e != nil {
+			return nil, e
+		}
+	}
+	return g, nil
+}
+
+// WithRetries is an option to call SetRetries(). Specify it first so that other options
+// benefit from the retries.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(g *Gyro) error {
+		g.SetRetries(retries, backoff)
+		return nil
+	}
+}
+
+// WithFrequency is an option to call SetFrequency(), which also turns the sensor on.
+func WithFrequency(value float64) Option {
+	return func(g *Gyro) error {
+		_, e := g.SetFrequency(value)
+		return e
+	}
+}
+
+// WithFullScale is an option to call SetFullScale().
+func WithFullScale(value float64) Option {
+	return func(g *Gyro) error {
+		return g.SetFullScale(value)
+	}
+}
+
+// WithBandwidth is an option to call SetBandwidth(). Specify it after WithFrequency().
+func WithBandwidth(hz int) Option {
+	return func(g *Gyro) error {
+		return g.SetBandwidth(hz)
+	}
+}
+
+// WithHighPassFilter is an option to call SetHighPassFilter() and EnableHighPassFilterOutput().
+func WithHighPassFilter(mode HPFMode, cutoff int) Option {
+	return func(g *Gyro) error {
+		if e := g.SetHighPassFilter(mode, cutoff); e != nil {
+			return e
+		}
+		return g.EnableHighPassFilterOutput(true)
+	}
+}
+
+// WithBias is an option to call SetBias().
+func WithBias(bias r3.Vector) Option {
+	return func(g *Gyro) error {
+		g.SetBias(bias)
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package l3gd
+
+import (
+	"fmt"
+
+	"github.com/golang/geo/r3"
+)
+
+// axisMap permutes and negates the axes of readings, see SetAxisMap().
+type axisMap struct {
+	remap [3]int
+	signs [3]float64
+}
+
+var identityAxisMap = axisMap{remap: [3]int{0, 1, 2}, signs: [3]float64{1, 1, 1}}
+
+func (m *axisMap) apply(v r3.Vector) r3.Vector {
+	in := [3]float64{v.X, v.Y, v.Z}
+	return r3.Vector{
+		X: m.signs[0] * in[m.remap[0]],
+		Y: m.signs[1] * in[m.remap[1]],
+		Z: m.signs[2] * in[m.remap[2]],
+	}
+}
+
+// SetAxisMap corrects readings for the way the sensor is mounted: X, Y and Z of a reading
+// are taken from the sensor axes remap[0], remap[1] and remap[2] (0 is X, 1 is Y, 2 is Z),
+// multiplied by signs (1 or -1). For example, a sensor rotated by 90° counterclockwise about Z
+// is corrected by remap {1, 0, 2} and signs {-1, 1, 1}. The default is no remapping.
+// The mapping is applied after subtracting Offset and applying scale trim, which therefore
+// stay in the sensor frame.
+func (g *Gyro) SetAxisMap(remap [3]int, signs [3]int) error {
+	var m axisMap
+	var seen [3]bool
+	for i := range remap {
+		if remap[i] < 0 || remap[i] > 2 || seen[remap[i]] {
+			return fmt.Errorf("axis remap %v is not a permutation of 0, 1, 2", remap)
+		}
+		seen[remap[i]] = true
+		if signs[i] != 1 && signs[i] != -1 {
+			return fmt.Errorf("axis signs %v must be 1 or -1", signs)
+		}
+		m.remap[i] = remap[i]
+		m.signs[i] = float64(signs[i])
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.axisMap = m
+	return nil
+}
+
+// Frame is a coordinate frame convention for readings, see SetFrame().
+type Frame int
+
+const (
+	// FrameSensor is the sensor frame, as printed on the chip (and most boards). It is the default.
+	FrameSensor Frame = iota
+	// FrameFLU is X forward, Y left, Z up (as in ROS, also known as "ENU-style" body frame).
+	FrameFLU
+	// FrameFRD is X forward, Y right, Z down (as in aerospace, also known as "NED-style" body frame).
+	FrameFRD
+)
+
+// frameSigns are signs applied to each axis for each Frame, from the sensor frame assumed to be
+// X forward, Z up.
+var frameSigns = [][3]float64{
+	{1, 1, 1},
+	{1, 1, 1},
+	{1, -1, -1},
+}
+
+// SetFrame makes readings conform to the frame convention, assuming that the sensor X axis points
+// forward and Z axis points up, either because of the way it is mounted, or after correcting
+// for that with SetAxisMap(). Both frames are right-handed, like the sensor frame. The default is
+// FrameSensor, i.e. no conversion.
+func (g *Gyro) SetFrame(frame Frame) error {
+	if frame < FrameSensor || frame > FrameFRD {
+		return fmt.Errorf("unsupported frame %d", frame)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.frame = frame
+	return nil
+}
+
+// output converts angular speed (in degrees per second) read from the sensor into the value
+// returned to the user.
+func (g *Gyro) output(v r3.Vector) r3.Vector {
+	v = v.Sub(g.Offset)
+	v = g.axisMap.apply(r3.Vector{
+		X: v.X * g.scaleTrim.X,
+		Y: v.Y * g.scaleTrim.Y,
+		Z: v.Z * g.scaleTrim.Z,
+	})
+	signs := frameSigns[g.frame]
+	return r3.Vector{X: signs[0] * v.X, Y: signs[1] * v.Y, Z: signs[2] * v.Z}
+}
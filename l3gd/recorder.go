@@ -0,0 +1,86 @@
+package l3gd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Recorder keeps the last samples read from Gyro in a circular buffer, e.g. to capture the motion
+// around an event detected with the threshold interrupt for later analysis.
+type Recorder struct {
+	gyro    *Gyro
+	mu      sync.Mutex
+	buffer  []Sample
+	next    int
+	samples int
+}
+
+// NewRecorder creates a new recorder keeping size last samples read from g.
+func NewRecorder(g *Gyro, size int) (*Recorder, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("buffer size must be positive, got %d", size)
+	}
+	return &Recorder{gyro: g, buffer: make([]Sample, size)}, nil
+}
+
+// Start starts recording samples from ReadStream() at hz in background, until ctx is cancelled,
+// Gyro is closed or a stream error occurs. Only Time and DPS fields of the recorded samples are
+// set. The returned channel receives the error which has stopped recording (ctx.Err() or
+// ErrClosed respectively) and is closed then. minimu9.DataAvailabilityError warnings are ignored.
+func (r *Recorder) Start(ctx context.Context, hz int) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		samples, errs := r.gyro.ReadStream(ctx, hz)
+		var streamErr error
+		for samples != nil || errs != nil {
+			select {
+			case v, ok := <-samples:
+				if !ok {
+					samples = nil
+					continue
+				}
+				r.add(Sample{Time: time.Now(), DPS: v})
+			case e, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				if !isDataAvailabilityError(e) {
+					streamErr = e
+				}
+			}
+		}
+		if streamErr == nil {
+			streamErr = ctx.Err()
+		}
+		if streamErr == nil {
+			streamErr = ErrClosed
+		}
+		done <- streamErr
+	}()
+	return done
+}
+
+func (r *Recorder) add(s Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buffer[r.next] = s
+	r.next = (r.next + 1) % len(r.buffer)
+	if r.samples < len(r.buffer) {
+		r.samples++
+	}
+}
+
+// Dump returns a copy of the recorded samples, newest first. It can be called while recording.
+func (r *Recorder) Dump() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dump := make([]Sample, r.samples)
+	for i := range dump {
+		dump[i] = r.buffer[(r.next-1-i+len(r.buffer))%len(r.buffer)]
+	}
+	return dump
+}
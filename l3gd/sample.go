@@ -0,0 +1,117 @@
+package l3gd
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+// Sample is a snapshot of the sensor output registers.
+type Sample struct {
+	// Time is when the sample was read.
+	Time time.Time
+	// Raw is angular speed in raw counts, as measured by the sensor.
+	Raw minimu9.IntVector
+	// DPS is angular speed in degrees per second, as returned by ReadTimed().
+	DPS r3.Vector
+	// Temperature is as returned by ReadTemperature().
+	Temperature int
+	// Status is decoded STATUS register.
+	Status StatusFlags
+}
+
+// ReadAll reads temperature, status and angular speed in a single transaction, as they are
+// stored in contiguous registers, which gives a coherent snapshot with minimal bus traffic.
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (g *Gyro) ReadAll() (Sample, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// OUT_TEMP, STATUS, OUT_X_L .. OUT_Z_H.
+	data := make([]byte, 8)
+	// Set MSB for the slave to advance the register on every read.
+	if e := minimu9.ReadFullSliceFromReg(g.bus, g.address, regOutTemp|(1<<7), data); e != nil {
+		return Sample{}, e
+	}
+	s := Sample{
+		Time:        time.Now(),
+		Temperature: decodeTemperature(data[0]),
+		Status:      decodeStatus(data[1]),
+		Raw:         minimu9.DecodeVector(data[2:], g.byteOrder),
+	}
+	if g.tempBias != nil {
+		// Use the temperature read anyway rather than reading it again in updateTempBias().
+		g.Offset = g.tempBias.Bias(s.Temperature)
+	}
+	s.DPS = g.output(s.Raw.R3().Mul(scaleRatio[g.fullScaleIndex]))
+	return s, minimu9.StatusError(data[1])
+}
+
+// sampleJSON is the JSON representation of Sample, with fields in a stable order.
+type sampleJSON struct {
+	Time           time.Time `json:"time"`
+	X              float64   `json:"x"`
+	Y              float64   `json:"y"`
+	Z              float64   `json:"z"`
+	Temperature    int       `json:"temperature"`
+	XDataAvailable bool      `json:"xDataAvailable"`
+	YDataAvailable bool      `json:"yDataAvailable"`
+	ZDataAvailable bool      `json:"zDataAvailable"`
+	DataAvailable  bool      `json:"dataAvailable"`
+	XOverrun       bool      `json:"xOverrun"`
+	YOverrun       bool      `json:"yOverrun"`
+	ZOverrun       bool      `json:"zOverrun"`
+	Overrun        bool      `json:"overrun"`
+}
+
+// MarshalJSON encodes the sample as a flat JSON object, e.g. for logging: time (RFC 3339),
+// x, y and z in degrees per second, temperature and status flags.
+func (s Sample) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sampleJSON{
+		Time:           s.Time,
+		X:              s.DPS.X,
+		Y:              s.DPS.Y,
+		Z:              s.DPS.Z,
+		Temperature:    s.Temperature,
+		XDataAvailable: s.Status.XDataAvailable,
+		YDataAvailable: s.Status.YDataAvailable,
+		ZDataAvailable: s.Status.ZDataAvailable,
+		DataAvailable:  s.Status.DataAvailable,
+		XOverrun:       s.Status.XOverrun,
+		YOverrun:       s.Status.YOverrun,
+		ZOverrun:       s.Status.ZOverrun,
+		Overrun:        s.Status.Overrun,
+	})
+}
+
+// SampleCSVHeader returns CSV column names matching Sample.CSVRow(), same as JSON field names.
+func SampleCSVHeader() []string {
+	return []string{
+		"time", "x", "y", "z", "temperature",
+		"xDataAvailable", "yDataAvailable", "zDataAvailable", "dataAvailable",
+		"xOverrun", "yOverrun", "zOverrun", "overrun",
+	}
+}
+
+// CSVRow formats the sample as CSV fields (e.g. for encoding/csv Writer) in the order of
+// SampleCSVHeader(): time (RFC 3339), x, y and z in degrees per second, temperature and
+// status flags.
+func (s Sample) CSVRow() []string {
+	row := []string{
+		s.Time.Format(time.RFC3339Nano),
+		strconv.FormatFloat(s.DPS.X, 'g', -1, 64),
+		strconv.FormatFloat(s.DPS.Y, 'g', -1, 64),
+		strconv.FormatFloat(s.DPS.Z, 'g', -1, 64),
+		strconv.Itoa(s.Temperature),
+	}
+	for _, flag := range []bool{
+		s.Status.XDataAvailable, s.Status.YDataAvailable, s.Status.ZDataAvailable,
+		s.Status.DataAvailable, s.Status.XOverrun, s.Status.YOverrun, s.Status.ZOverrun,
+		s.Status.Overrun,
+	} {
+		row = append(row, strconv.FormatBool(flag))
+	}
+	return row
+}
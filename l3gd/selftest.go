@@ -0,0 +1,63 @@
+package l3gd
+
+import (
+	"math"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+const (
+	// selfTestSamples is how many samples are averaged for each self-test measurement.
+	selfTestSamples = 20
+	// selfTestSettleSamples is how many samples are discarded after toggling self-test mode.
+	selfTestSettleSamples = 5
+)
+
+// selfTestOutputChange is a typical output change in self-test mode per full scale, in degrees/s.
+var selfTestOutputChange = []float64{130, 200, 530}
+
+// SelfTest applies a known electrostatic force to the sensor and checks that the output changes
+// accordingly on every axis. The datasheet only specifies a typical output change, so a change
+// within 50%..150% of it is considered a pass. Returns true if all axes pass.
+// NOTE: during self-test, the sensor has to be static (not moving).
+func (g *Gyro) SelfTest() (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	baseline, e := g.average(selfTestSamples)
+	if e != nil {
+		return false, e
+	}
+	// Positive sign self-test (ST = 01).
+	if e = g.setSelfTest(1 << 1); e != nil {
+		return false, e
+	}
+	stressed, e := g.settleAndAverage()
+	if disableErr := g.setSelfTest(0); e == nil {
+		e = disableErr
+	}
+	if e != nil {
+		return false, e
+	}
+	typical := selfTestOutputChange[g.fullScaleIndex]
+	delta := stressed.Sub(baseline).Abs()
+	for _, d := range []float64{delta.X, delta.Y, delta.Z} {
+		if math.Abs(d-typical) > typical/2 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (g *Gyro) setSelfTest(selfTestBits byte) error {
+	return minimu9.WriteBitsToReg(g.bus, g.address, regCtrl4, (1<<1)|(1<<2), selfTestBits)
+}
+
+func (g *Gyro) settleAndAverage() (v r3.Vector, e error) {
+	for i := 0; i < selfTestSettleSamples; i++ {
+		if _, e = g.readFresh(); e != nil {
+			return
+		}
+	}
+	return g.average(selfTestSamples)
+}
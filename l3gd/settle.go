@@ -0,0 +1,94 @@
+package l3gd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+)
+
+// settleTimeConstants is how many low-pass filter time constants WaitSettle() waits for.
+const settleTimeConstants = 5
+
+// WaitSettle waits for the output to settle after the sensor has been turned on or reconfigured
+// (e.g. with SetFrequency(), SetPowerMode() or SetBandwidth()), by reading and discarding new
+// samples. The number of samples depends on the current data rate and low-pass filter bandwidth:
+// it covers 5 filter time constants plus the first sample after turn-on, e.g. 3 samples at
+// 12.5 Hz and 23 samples at 800 Hz with 30 Hz bandwidth.
+// Returns ErrNoNewData if the sensor is not measuring (e.g. it's not in normal mode).
+func (g *Gyro) WaitSettle() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cutoff, e := g.readBandwidth()
+	if e != nil {
+		return e
+	}
+	timeConstant := 1 / (2 * math.Pi * cutoff)
+	samples := 1 + int(math.Ceil(settleTimeConstants*timeConstant*g.frequency))
+	for i := 0; i < samples; i++ {
+		if _, e = g.readFresh(); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// readBandwidth reads low-pass filter cutoff, in Hz, see Bandwidth().
+func (g *Gyro) readBandwidth() (float64, error) {
+	ctrl1, e := g.bus.ReadByteFromReg(g.address, regCtrl1)
+	if e != nil {
+		return 0, e
+	}
+	lowOdr, e := g.bus.ReadByteFromReg(g.address, regLowOdr)
+	if e != nil {
+		return 0, e
+	}
+	ctrl5, e := g.bus.ReadByteFromReg(g.address, regCtrl5)
+	if e != nil {
+		return 0, e
+	}
+	return decodeBandwidth(ctrl1, lowOdr, ctrl5), nil
+}
+
+// stableWindow is for how long the output has to stay within the tolerance for
+// WaitUntilStable().
+const stableWindow = 200 * time.Millisecond
+
+// WaitUntilStable waits for the power-up transient to end, so that it's safe to calibrate, by
+// reading new samples until all of them stay within toleranceDPS (on every axis) of the first one
+// for 200ms at the current data rate. Unlike WaitSettle(), it adapts to the actual sensor, and
+// the sensor has to be static. Returns an error if the output hasn't stabilized within maxWait,
+// e.g. because the sensor is vibrating, or the sensor is not producing new samples.
+func (g *Gyro) WaitUntilStable(maxWait time.Duration, toleranceDPS float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if toleranceDPS <= 0 {
+		return fmt.Errorf("tolerance must be positive, got %v degrees/s", toleranceDPS)
+	}
+	required := int(math.Ceil(stableWindow.Seconds() * g.frequency))
+	deadline := time.Now().Add(maxWait)
+	var reference r3.Vector
+	stable := 0
+	for stable < required {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("output has not stabilized within %v", maxWait)
+		}
+		v, e := g.readScaled()
+		if isDataNotAvailable(e) {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		if e != nil && !isDataAvailabilityError(e) {
+			return e
+		}
+		d := v.Sub(reference)
+		if stable == 0 || math.Abs(d.X) > toleranceDPS || math.Abs(d.Y) > toleranceDPS ||
+			math.Abs(d.Z) > toleranceDPS {
+			reference = v
+			stable = 0
+		}
+		stable++
+	}
+	return nil
+}
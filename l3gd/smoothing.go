@@ -0,0 +1,66 @@
+package l3gd
+
+import (
+	"fmt"
+
+	"github.com/golang/geo/r3"
+)
+
+// SmoothedReader smooths angular speed read from Gyro with a moving average over a window of
+// the last samples, e.g. to suppress vibrations beyond the sensor's low-pass filter.
+type SmoothedReader struct {
+	gyro    *Gyro
+	window  []r3.Vector
+	next    int
+	samples int
+}
+
+// NewSmoothedReader creates a new smoothed reader averaging size last samples read from g.
+func NewSmoothedReader(g *Gyro, size int) (*SmoothedReader, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("window size must be positive, got %d", size)
+	}
+	return &SmoothedReader{gyro: g, window: make([]r3.Vector, size)}, nil
+}
+
+// Read reads a sample (as returned by Gyro.Read()), and returns an average of the samples in the
+// window, which has fewer samples than its size until enough reads are done. If there was no
+// new sample, it is not added to the window.
+// Note: err might be a warning about data "freshness" if it's minimu9.DataAvailabilityError.
+func (s *SmoothedReader) Read() (r3.Vector, error) {
+	v, e := s.gyro.Read()
+	if e != nil && !isDataAvailabilityError(e) {
+		return r3.Vector{}, e
+	}
+	if !isDataNotAvailable(e) {
+		s.window[s.next] = v
+		s.next = (s.next + 1) % len(s.window)
+		if s.samples < len(s.window) {
+			s.samples++
+		}
+	}
+	var sum r3.Vector
+	for _, sample := range s.window[:s.samples] {
+		sum = sum.Add(sample)
+	}
+	if s.samples == 0 {
+		return sum, e
+	}
+	return sum.Mul(1 / float64(s.samples)), e
+}
+
+// SetOversampling makes ReadDPS() (and Read()) return an average of n new samples, for cleaner
+// data without changing the bandwidth. The sensor has no hardware averaging, so it is done in
+// software: every read waits for n new samples, i.e. takes n / Frequency() seconds, and never
+// returns data "freshness" warnings; ErrNoNewData is returned instead if the sensor is not
+// measuring. Use n = 1 (the default) to turn it off. Other read methods, such as ReadTimed(),
+// ReadWithStatus(), ReadAll() and ReadFIFO(), always return single samples.
+func (g *Gyro) SetOversampling(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("oversampling must be positive, got %d", n)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.oversampling = n
+	return nil
+}
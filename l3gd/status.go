@@ -0,0 +1,32 @@
+package l3gd
+
+// StatusFlags is a decoded STATUS register.
+type StatusFlags struct {
+	// XDataAvailable, YDataAvailable and ZDataAvailable tell that a new measurement is available
+	// for the axis; DataAvailable tells that a new set of data is available for all axes.
+	XDataAvailable, YDataAvailable, ZDataAvailable, DataAvailable bool
+	// XOverrun, YOverrun and ZOverrun tell that a new measurement for the axis has overwritten
+	// the previous one before it was read; Overrun tells the same for the data set as a whole.
+	XOverrun, YOverrun, ZOverrun, Overrun bool
+}
+
+func decodeStatus(status byte) StatusFlags {
+	return StatusFlags{
+		XDataAvailable: status&(1<<0) != 0,
+		YDataAvailable: status&(1<<1) != 0,
+		ZDataAvailable: status&(1<<2) != 0,
+		DataAvailable:  status&(1<<3) != 0,
+		XOverrun:       status&(1<<4) != 0,
+		YOverrun:       status&(1<<5) != 0,
+		ZOverrun:       status&(1<<6) != 0,
+		Overrun:        status&(1<<7) != 0,
+	}
+}
+
+// Status reads the STATUS register without consuming the measurement.
+func (g *Gyro) Status() (StatusFlags, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	status, e := g.bus.ReadByteFromReg(g.address, regStatus)
+	return decodeStatus(status), e
+}
@@ -0,0 +1,216 @@
+package l3gd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+// ReadStream sets the output data rate to hz and starts reading samples (as returned by Read())
+// in background, at hz or the data rate actually set, whichever is lower (below 12.5 Hz, the
+// data rate is set to 12.5 Hz), until ctx is cancelled or Gyro is closed. Both returned
+// channels are closed then.
+// minimu9.DataAvailabilityError warnings are sent to the error channel without stopping the
+// stream (samples which were not updated are skipped); any other error stops the stream.
+// Both channels have to be drained by the caller, otherwise the stream stalls.
+// Changing configuration while streaming is only safe with Reconfigure().
+// See SetStreamTimeout() to detect a stalled bus.
+func (g *Gyro) ReadStream(ctx context.Context, hz int) (<-chan r3.Vector, <-chan error) {
+	samples := make(chan r3.Vector)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(samples)
+		defer close(errs)
+		if hz <= 0 {
+			errs <- fmt.Errorf("stream rate must be positive, got %d Hz", hz)
+			return
+		}
+		frequency, period, e := g.setStreamFrequency(hz)
+		if e == ErrClosed {
+			return
+		}
+		if e != nil {
+			errs <- e
+			return
+		}
+		g.mu.Lock()
+		timeout := time.Duration(float64(g.streamTimeout) * float64(time.Second) / frequency)
+		reset := g.streamReset
+		g.mu.Unlock()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-g.closed:
+				return
+			case <-ticker.C:
+			}
+			v, e := g.readStreamSample(timeout)
+			if e == ErrStreamTimeout && reset {
+				go g.resetAfterTimeout()
+			}
+			if e != nil {
+				select {
+				case errs <- e:
+				case <-ctx.Done():
+					return
+				case <-g.closed:
+					return
+				}
+				if _, ok := e.(*minimu9.DataAvailabilityError); !ok {
+					return
+				}
+				if isDataNotAvailable(e) {
+					continue
+				}
+			}
+			select {
+			case samples <- v:
+			case <-ctx.Done():
+				return
+			case <-g.closed:
+				return
+			}
+		}
+	}()
+	return samples, errs
+}
+
+// ErrStreamTimeout is sent by ReadStream() when a read has not completed in time, see
+// SetStreamTimeout().
+var ErrStreamTimeout = errors.New("stream read has timed out")
+
+// SetStreamTimeout makes ReadStream() send ErrStreamTimeout and stop if a read has not completed
+// within the specified number of data rate periods, e.g. because the bus has hung, so that the
+// fault is reported rather than the stream quietly stopping. With reset, Reset() is also called
+// then; since it needs the bus, it only takes place once the stalled read returns, if ever.
+// Zero periods disable the timeout, which is the default. It applies to streams started after
+// the call.
+func (g *Gyro) SetStreamTimeout(periods int, reset bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if periods < 0 {
+		return fmt.Errorf("stream timeout must not be negative, got %d periods", periods)
+	}
+	g.streamTimeout = periods
+	g.streamReset = reset
+	return nil
+}
+
+// readStreamSample reads a sample for ReadStream(), giving up after timeout unless it's zero.
+func (g *Gyro) readStreamSample(timeout time.Duration) (r3.Vector, error) {
+	read := func() (r3.Vector, error) {
+		g.streamMu.RLock()
+		defer g.streamMu.RUnlock()
+		return g.Read()
+	}
+	if timeout == 0 {
+		return read()
+	}
+	type result struct {
+		v r3.Vector
+		e error
+	}
+	// Buffered, so that a stalled read does not leak the goroutine once it returns.
+	results := make(chan result, 1)
+	go func() {
+		v, e := read()
+		results <- result{v, e}
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-results:
+		return r.v, r.e
+	case <-timer.C:
+		return r3.Vector{}, ErrStreamTimeout
+	}
+}
+
+// resetAfterTimeout calls Reset() for SetStreamTimeout() and logs the outcome.
+func (g *Gyro) resetAfterTimeout() {
+	e := g.Reset()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e != nil {
+		g.logf("reset after stream timeout has failed: %v", e)
+	} else {
+		g.logf("reset after stream timeout")
+	}
+}
+
+// Poll sets the output data rate to hz and calls fn for every sample (as returned by Read()),
+// at hz or the data rate actually set, whichever is lower, same as ReadStream(), until ctx is
+// cancelled. minimu9.DataAvailabilityError warnings
+// are passed to fn along with the sample; any other error stops polling and is returned.
+// Otherwise, Poll blocks until ctx is cancelled and returns ctx.Err(), or until Gyro is closed
+// and returns ErrClosed. Changing configuration while polling is only safe with Reconfigure().
+func (g *Gyro) Poll(ctx context.Context, hz int, fn func(r3.Vector, error)) error {
+	if hz <= 0 {
+		return fmt.Errorf("poll rate must be positive, got %d Hz", hz)
+	}
+	_, period, e := g.setStreamFrequency(hz)
+	if e != nil {
+		return e
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-g.closed:
+			return ErrClosed
+		case <-ticker.C:
+		}
+		g.streamMu.RLock()
+		v, e := g.Read()
+		g.streamMu.RUnlock()
+		if e != nil && !isDataAvailabilityError(e) {
+			return e
+		}
+		fn(v, e)
+	}
+}
+
+// setStreamFrequency sets the data rate for ReadStream() and Poll() to hz, or to the lowest
+// supported rate if hz is below it. Returns the rate actually set, and the period to read at,
+// so that reads are neither more frequent than requested nor than new samples. Returns ErrClosed
+// without writing to the sensor if Gyro has been closed, so that it stays powered down.
+func (g *Gyro) setStreamFrequency(hz int) (float64, time.Duration, error) {
+	value := math.Max(float64(hz), frequencies[0])
+	if value > frequencies[len(frequencies)-1] {
+		return 0, 0, fmt.Errorf("data rate %v Hz is out of range %v .. %v Hz",
+			value, frequencies[0], frequencies[len(frequencies)-1])
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	select {
+	case <-g.closed:
+		return 0, 0, ErrClosed
+	default:
+	}
+	frequency, e := g.setFrequency(value)
+	if e != nil {
+		return 0, 0, e
+	}
+	return frequency, time.Duration(float64(time.Second) / math.Min(float64(hz), frequency)), nil
+}
+
+// Reconfigure pauses all ReadStream() and Poll() loops (waiting for the reads in progress to
+// complete), calls fn, which can use any Gyro methods to change the configuration, and resumes
+// the loops, so that they never read while the configuration is partially changed. Calling other
+// setters directly while streaming is not supported. The loops keep their rate, even if fn
+// changes the data rate. Returns the error returned by fn.
+func (g *Gyro) Reconfigure(fn func(*Gyro) error) error {
+	g.streamMu.Lock()
+	defer g.streamMu.Unlock()
+	return fn(g)
+}
@@ -0,0 +1,30 @@
+package l3gd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestStreamAfterClose(t *testing.T) {
+	bus := newTestBus()
+	g := NewGyro(bus, testAddress)
+	if e := g.Close(); e != nil {
+		t.Fatal(e)
+	}
+	ctrl1 := bus.Register(testAddress, regCtrl1)
+	samples, errs := g.ReadStream(context.Background(), 100)
+	for range samples {
+		t.Error("ReadStream(): expected no samples after Close()")
+	}
+	for e := range errs {
+		t.Errorf("ReadStream(): expected no errors after Close(), got %v", e)
+	}
+	if e := g.Poll(context.Background(), 100, func(r3.Vector, error) {}); e != ErrClosed {
+		t.Errorf("Poll(): expected ErrClosed, got %v", e)
+	}
+	if value := bus.Register(testAddress, regCtrl1); value != ctrl1 {
+		t.Errorf("expected CTRL1 to stay 0x%02X after Close(), got 0x%02X", ctrl1, value)
+	}
+}
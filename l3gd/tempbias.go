@@ -0,0 +1,105 @@
+package l3gd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/geo/r3"
+)
+
+// tempBiasSamples is how many samples FitTempBias() averages for each bias measurement.
+const tempBiasSamples = 50
+
+// TempCompensatedBias is a linear model of zero-rate bias drift with temperature: for each axis,
+// bias = Intercept + Slope * temperature, in degrees per second, where temperature is as returned
+// by ReadTemperature() (so the model is only valid for the chip it was fitted on).
+type TempCompensatedBias struct {
+	Slope, Intercept r3.Vector
+}
+
+// Bias returns the bias at the temperature, in degrees per second.
+func (b *TempCompensatedBias) Bias(temperature int) r3.Vector {
+	return b.Intercept.Add(b.Slope.Mul(float64(temperature)))
+}
+
+// SetTempCompensatedBias makes every read which subtracts Offset (e.g. Read(), ReadTimed(),
+// ReadAll(), ReadFIFO() and ReadAxis()) read the temperature and set Offset to the bias the model
+// gives for it before reading, or stops doing so if model is nil. ReadAll() uses the temperature
+// it reads anyway, and ReadFIFO() uses the same Offset for all samples drained.
+func (g *Gyro) SetTempCompensatedBias(model *TempCompensatedBias) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tempBias = model
+}
+
+// updateTempBias sets Offset according to the temperature compensation model, if any.
+func (g *Gyro) updateTempBias() error {
+	if g.tempBias == nil {
+		return nil
+	}
+	t, e := g.bus.ReadByteFromReg(g.address, regOutTemp)
+	if e != nil {
+		return e
+	}
+	g.Offset = g.tempBias.Bias(decodeTemperature(t))
+	return nil
+}
+
+// FitTempBias measures bias (as CalibrateBias() does, without changing Offset) and temperature
+// the specified number of times, waiting for interval between measurements, and fits a linear
+// model through them with least squares. Run it while the device warms up, so that the
+// temperature actually changes; the model can then be set with SetTempCompensatedBias().
+// The Gyro is not locked while waiting, so other methods can still be called.
+// NOTE: during measurement, the sensor has to be static (not moving).
+func (g *Gyro) FitTempBias(points int, interval time.Duration) (TempCompensatedBias, error) {
+	if points < 2 {
+		return TempCompensatedBias{}, fmt.Errorf("at least 2 points are required, got %d", points)
+	}
+	temperatures := make([]float64, points)
+	biases := make([]r3.Vector, points)
+	for i := 0; i < points; i++ {
+		if i > 0 {
+			time.Sleep(interval)
+		}
+		var e error
+		if temperatures[i], biases[i], e = g.measureTempBias(); e != nil {
+			return TempCompensatedBias{}, e
+		}
+	}
+	var meanT float64
+	var meanBias r3.Vector
+	for i := range temperatures {
+		meanT += temperatures[i]
+		meanBias = meanBias.Add(biases[i])
+	}
+	meanT /= float64(points)
+	meanBias = meanBias.Mul(1 / float64(points))
+	var varianceT float64
+	var covariance r3.Vector
+	for i := range temperatures {
+		dt := temperatures[i] - meanT
+		varianceT += dt * dt
+		covariance = covariance.Add(biases[i].Sub(meanBias).Mul(dt))
+	}
+	if varianceT == 0 {
+		return TempCompensatedBias{}, errors.New("temperature did not change during measurement")
+	}
+	slope := covariance.Mul(1 / varianceT)
+	return TempCompensatedBias{
+		Slope:     slope,
+		Intercept: meanBias.Sub(slope.Mul(meanT)),
+	}, nil
+}
+
+// measureTempBias measures temperature and bias for FitTempBias().
+func (g *Gyro) measureTempBias() (float64, r3.Vector, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	bias, e := g.average(tempBiasSamples)
+	if e != nil {
+		return 0, r3.Vector{}, e
+	}
+	t, e := g.bus.ReadByteFromReg(g.address, regOutTemp)
+	return float64(decodeTemperature(t)), bias, e
+}
@@ -0,0 +1,55 @@
+package l3gd
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+)
+
+func TestTempCompensatedBiasAppliedInEveryRead(t *testing.T) {
+	g := NewGyro(newTestBus(), testAddress)
+	want, _, e := g.ReadTimed()
+	if e != nil {
+		t.Fatal(e)
+	}
+	bias := r3.Vector{X: 1, Y: 2, Z: 3}
+	want = want.Sub(bias)
+	g.SetTempCompensatedBias(&TempCompensatedBias{Intercept: bias})
+
+	reads := map[string]func() (r3.Vector, error){
+		"ReadTimed()": func() (r3.Vector, error) {
+			v, _, e := g.ReadTimed()
+			return v, e
+		},
+		"ReadWithStatus()": func() (r3.Vector, error) {
+			v, _, e := g.ReadWithStatus()
+			return v, e
+		},
+		"ReadAll()": func() (r3.Vector, error) {
+			s, e := g.ReadAll()
+			return s.DPS, e
+		},
+		"ReadDPSChecked()": g.ReadDPSChecked,
+		"ReadConsistent()": g.ReadConsistent,
+	}
+	for name, read := range reads {
+		g.Offset = r3.Vector{}
+		v, e := read()
+		if e != nil {
+			t.Errorf("%s: %v", name, e)
+			continue
+		}
+		if v.Sub(want).Norm() > 1e-9 {
+			t.Errorf("%s: expected %v with the bias subtracted, got %v", name, want, v)
+		}
+	}
+
+	g.Offset = r3.Vector{}
+	x, e := g.ReadAxis(AxisX)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if x != want.X {
+		t.Errorf("ReadAxis(): expected %v with the bias subtracted, got %v", want.X, x)
+	}
+}
@@ -0,0 +1,47 @@
+package l3gd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dasfoo/minimu9"
+	"github.com/golang/geo/r3"
+)
+
+// EnableWakeOnMotion configures the sensor to assert INT1 pin when the angular rate on any axis
+// exceeds thresholdDPS (in degrees per second, at the current full scale), so that the host can
+// sleep until the sensor moves. Only enabled axes (see SetAxesEnabled()) are monitored.
+// The interrupt is latched until ThresholdInterruptSource() is read.
+// The sensor has to keep measuring to generate interrupts, so neither power-down nor sleep mode
+// will do; instead, the lowest viable power state is used: normal mode at 12.5 Hz data rate.
+// The interrupt generator is fed with high-pass filtered data (see SetHighPassFilter()), so that
+// zero-rate bias does not count as motion; if the output goes through the low-pass filter (see
+// SetBandwidth()), it is high-pass filtered too. Use ConfigureThresholdInterrupt() to disable it.
+func (g *Gyro) EnableWakeOnMotion(thresholdDPS float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if threshold := g.dpsToCounts(thresholdDPS); threshold <= 0 || threshold > maxThreshold {
+		return fmt.Errorf("wake-on-motion threshold %v degrees/s is out of range 0..%v",
+			thresholdDPS, g.countsToDPS(maxThreshold))
+	}
+	if g.axesBits == 0 {
+		return errors.New("wake-on-motion requires at least one axis enabled")
+	}
+	if _, e := g.setFrequency(frequencies[0]); e != nil {
+		return e
+	}
+	// HPen, IG_Sel = 01: high-pass filtered data for the interrupt generator.
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regCtrl5, (1<<4)|(1<<3)|(1<<2),
+		(1<<4)|(1<<2)); e != nil {
+		return e
+	}
+	if e := minimu9.WriteBitsToReg(g.bus, g.address, regInt1Cfg, 1<<6, 1<<6); e != nil {
+		return e
+	}
+	return g.configureThresholdInterrupt(ThresholdInterrupt{
+		XHigh:        g.axesBits&(1<<0) != 0,
+		YHigh:        g.axesBits&(1<<1) != 0,
+		ZHigh:        g.axesBits&(1<<2) != 0,
+		ThresholdDPS: r3.Vector{X: thresholdDPS, Y: thresholdDPS, Z: thresholdDPS},
+	})
+}
@@ -0,0 +1,55 @@
+package minimu9
+
+// Logger receives debug messages from the drivers. It is satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// loggingBus is a Bus which logs writes and failed operations.
+type loggingBus struct {
+	bus    Bus
+	logger Logger
+}
+
+// NewLoggingBus wraps bus to log all register writes (with address, register and value) and
+// failed operations (with the error) to logger. Successful reads are not logged, since they are
+// too frequent. When wrapped with NewRetryingBus(), every failed attempt is logged.
+func NewLoggingBus(bus Bus, logger Logger) Bus {
+	return &loggingBus{bus: bus, logger: logger}
+}
+
+func (b *loggingBus) ReadByteFromReg(addr, reg byte) (byte, error) {
+	value, e := b.bus.ReadByteFromReg(addr, reg)
+	if e != nil {
+		b.logger.Printf("read 0x%02X/0x%02X failed: %v", addr, reg, e)
+	}
+	return value, e
+}
+
+func (b *loggingBus) ReadSliceFromReg(addr, reg byte, data []byte) (int, error) {
+	n, e := b.bus.ReadSliceFromReg(addr, reg, data)
+	if e != nil {
+		b.logger.Printf("read %d bytes from 0x%02X/0x%02X failed: %v", len(data), addr, reg, e)
+	}
+	return n, e
+}
+
+func (b *loggingBus) WriteByteToReg(addr, reg, value byte) error {
+	e := b.bus.WriteByteToReg(addr, reg, value)
+	if e != nil {
+		b.logger.Printf("write 0x%02X/0x%02X = 0x%02X failed: %v", addr, reg, value, e)
+	} else {
+		b.logger.Printf("write 0x%02X/0x%02X = 0x%02X", addr, reg, value)
+	}
+	return e
+}
+
+func (b *loggingBus) WriteSliceToReg(addr, reg byte, data []byte) (int, error) {
+	n, e := b.bus.WriteSliceToReg(addr, reg, data)
+	if e != nil {
+		b.logger.Printf("write 0x%02X/0x%02X = % X failed: %v", addr, reg, data, e)
+	} else {
+		b.logger.Printf("write 0x%02X/0x%02X = % X", addr, reg, data)
+	}
+	return n, e
+}
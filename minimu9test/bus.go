@@ -0,0 +1,90 @@
+// Package minimu9test provides a fake in-memory bus for testing code using minimu9 drivers
+// without hardware.
+package minimu9test
+
+import (
+	"sync"
+
+	"github.com/dasfoo/minimu9"
+)
+
+var _ minimu9.Bus = (*Bus)(nil)
+
+// Bus is a fake minimu9.Bus which keeps register values of every slave address in memory.
+// Like with the real sensors, setting MSB of the register address in slice reads and writes
+// makes the register advance on every byte; otherwise the same register is accessed repeatedly.
+// It is safe for concurrent use.
+type Bus struct {
+	mu        sync.Mutex
+	registers map[byte]*[128]byte
+}
+
+// NewBus creates a new fake bus with all registers of all slaves set to zero.
+func NewBus() *Bus {
+	return &Bus{registers: make(map[byte]*[128]byte)}
+}
+
+// slave returns registers for the address. Must be called with b.mu held.
+func (b *Bus) slave(addr byte) *[128]byte {
+	registers, ok := b.registers[addr]
+	if !ok {
+		registers = new([128]byte)
+		b.registers[addr] = registers
+	}
+	return registers
+}
+
+// Register returns current value of a register, e.g. to verify what the driver has written.
+func (b *Bus) Register(addr, reg byte) byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.slave(addr)[reg&0x7f]
+}
+
+// SetRegister sets value of a register, e.g. to simulate a measurement.
+func (b *Bus) SetRegister(addr, reg, value byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.slave(addr)[reg&0x7f] = value
+}
+
+// ReadByteFromReg reads a register value.
+func (b *Bus) ReadByteFromReg(addr, reg byte) (byte, error) {
+	return b.Register(addr, reg), nil
+}
+
+// WriteByteToReg writes a register value.
+func (b *Bus) WriteByteToReg(addr, reg, value byte) error {
+	b.SetRegister(addr, reg, value)
+	return nil
+}
+
+// ReadSliceFromReg reads len(data) bytes starting from the register.
+func (b *Bus) ReadSliceFromReg(addr, reg byte, data []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	registers := b.slave(addr)
+	for i := range data {
+		data[i] = registers[advance(reg, i)]
+	}
+	return len(data), nil
+}
+
+// WriteSliceToReg writes data starting from the register.
+func (b *Bus) WriteSliceToReg(addr, reg byte, data []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	registers := b.slave(addr)
+	for i, value := range data {
+		registers[advance(reg, i)] = value
+	}
+	return len(data), nil
+}
+
+// advance returns the register accessed by i-th byte of a slice operation starting at reg.
+func advance(reg byte, i int) byte {
+	if reg&(1<<7) == 0 {
+		return reg
+	}
+	return byte(int(reg&0x7f)+i) & 0x7f
+}
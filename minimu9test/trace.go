@@ -0,0 +1,240 @@
+package minimu9test
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dasfoo/minimu9"
+)
+
+var (
+	_ minimu9.Bus = (*RecordingBus)(nil)
+	_ minimu9.Bus = (*ReplayBus)(nil)
+)
+
+// A trace is a text with one bus operation per line:
+//
+//	read 6b 27 0f
+//	write 6b 20 0f
+//	read 6b a8 ! i2c: timeout
+//
+// i.e. the operation, slave address and register in hex, followed by either the bytes
+// transferred in hex ("-" if none), or "!" and the error message.
+
+const (
+	traceRead  = "read"
+	traceWrite = "write"
+)
+
+// traceOperation is a single line of a trace.
+type traceOperation struct {
+	kind         string
+	address, reg byte
+	data         []byte
+	failed       bool
+	err          string
+	line         int
+}
+
+func (o *traceOperation) String() string {
+	if o.failed {
+		return fmt.Sprintf("%s %02x %02x ! %s", o.kind, o.address, o.reg, o.err)
+	}
+	data := hex.EncodeToString(o.data)
+	if data == "" {
+		data = "-"
+	}
+	return fmt.Sprintf("%s %02x %02x %s", o.kind, o.address, o.reg, data)
+}
+
+// RecordingBus is a minimu9.Bus which writes a trace of all operations on the underlying bus,
+// e.g. to reproduce a problem seen on real hardware later with ReplayBus.
+// It is safe for concurrent use if the underlying bus is.
+type RecordingBus struct {
+	bus minimu9.Bus
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewRecordingBus creates a new bus recording a trace of operations on bus to w.
+func NewRecordingBus(bus minimu9.Bus, w io.Writer) *RecordingBus {
+	return &RecordingBus{bus: bus, w: w}
+}
+
+// Err returns the first error writing the trace, if any.
+func (b *RecordingBus) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
+
+func (b *RecordingBus) record(kind string, addr, reg byte, data []byte, e error) {
+	o := traceOperation{kind: kind, address: addr, reg: reg, data: data}
+	if e != nil {
+		o.failed, o.err = true, e.Error()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == nil {
+		_, b.err = fmt.Fprintln(b.w, o.String())
+	}
+}
+
+// ReadByteFromReg reads a register value and records it.
+func (b *RecordingBus) ReadByteFromReg(addr, reg byte) (byte, error) {
+	value, e := b.bus.ReadByteFromReg(addr, reg)
+	b.record(traceRead, addr, reg, []byte{value}, e)
+	return value, e
+}
+
+// WriteByteToReg writes a register value and records it.
+func (b *RecordingBus) WriteByteToReg(addr, reg, value byte) error {
+	e := b.bus.WriteByteToReg(addr, reg, value)
+	b.record(traceWrite, addr, reg, []byte{value}, e)
+	return e
+}
+
+// ReadSliceFromReg reads bytes starting from the register and records them.
+func (b *RecordingBus) ReadSliceFromReg(addr, reg byte, data []byte) (int, error) {
+	n, e := b.bus.ReadSliceFromReg(addr, reg, data)
+	b.record(traceRead, addr, reg, data[:n], e)
+	return n, e
+}
+
+// WriteSliceToReg writes bytes starting from the register and records them.
+func (b *RecordingBus) WriteSliceToReg(addr, reg byte, data []byte) (int, error) {
+	n, e := b.bus.WriteSliceToReg(addr, reg, data)
+	b.record(traceWrite, addr, reg, data[:n], e)
+	return n, e
+}
+
+// ReplayBus is a minimu9.Bus which plays back a trace recorded by RecordingBus: reads return
+// the recorded data or errors, and every operation is verified to match the trace, so that
+// the driver behavior can be reproduced deterministically without hardware.
+// It is safe for concurrent use, although the order of concurrent operations is undefined.
+type ReplayBus struct {
+	mu         sync.Mutex
+	operations []traceOperation
+	next       int
+}
+
+// NewReplayBus reads the whole trace from r and creates a new bus replaying it.
+func NewReplayBus(r io.Reader) (*ReplayBus, error) {
+	b := &ReplayBus{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		o, e := parseTraceOperation(text)
+		if e != nil {
+			return nil, fmt.Errorf("trace line %d: %v", line, e)
+		}
+		o.line = line
+		b.operations = append(b.operations, o)
+	}
+	return b, scanner.Err()
+}
+
+func parseTraceOperation(text string) (o traceOperation, e error) {
+	fields := strings.SplitN(text, " ", 4)
+	if len(fields) != 4 {
+		return o, fmt.Errorf("expected 4 fields, got %q", text)
+	}
+	o.kind = fields[0]
+	if o.kind != traceRead && o.kind != traceWrite {
+		return o, fmt.Errorf("unknown operation %q", o.kind)
+	}
+	for i, field := range []*byte{&o.address, &o.reg} {
+		value, e := strconv.ParseUint(fields[i+1], 16, 8)
+		if e != nil {
+			return o, e
+		}
+		*field = byte(value)
+	}
+	switch {
+	case strings.HasPrefix(fields[3], "!"):
+		o.failed, o.err = true, strings.TrimSpace(strings.TrimPrefix(fields[3], "!"))
+	case fields[3] == "-":
+	default:
+		o.data, e = hex.DecodeString(fields[3])
+	}
+	return o, e
+}
+
+// Remaining returns the number of operations in the trace not replayed yet.
+func (b *ReplayBus) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.operations) - b.next
+}
+
+// replay verifies that the next operation in the trace matches and returns it.
+func (b *ReplayBus) replay(kind string, addr, reg byte, data []byte) (*traceOperation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	actual := traceOperation{kind: kind, address: addr, reg: reg}
+	if kind == traceWrite {
+		actual.data = data
+	}
+	if b.next >= len(b.operations) {
+		return nil, fmt.Errorf("trace is over, got %q", actual.String())
+	}
+	o := &b.operations[b.next]
+	if o.kind != kind || o.address != addr || o.reg != reg ||
+		(!o.failed && len(o.data) > len(data)) ||
+		(kind == traceWrite && !o.failed && string(o.data) != string(data)) {
+		return nil, fmt.Errorf("trace line %d: expected %q, got %q", o.line, o.String(),
+			actual.String())
+	}
+	b.next++
+	if o.failed {
+		return o, errors.New(o.err)
+	}
+	return o, nil
+}
+
+// ReadByteFromReg returns the value recorded in the trace.
+func (b *ReplayBus) ReadByteFromReg(addr, reg byte) (byte, error) {
+	value := make([]byte, 1)
+	o, e := b.replay(traceRead, addr, reg, value)
+	if e != nil {
+		return 0, e
+	}
+	if len(o.data) == 0 {
+		return 0, fmt.Errorf("trace line %d: no data recorded", o.line)
+	}
+	return o.data[0], nil
+}
+
+// WriteByteToReg verifies that the value matches the trace.
+func (b *ReplayBus) WriteByteToReg(addr, reg, value byte) error {
+	_, e := b.replay(traceWrite, addr, reg, []byte{value})
+	return e
+}
+
+// ReadSliceFromReg returns the data recorded in the trace, which may be shorter than data.
+func (b *ReplayBus) ReadSliceFromReg(addr, reg byte, data []byte) (int, error) {
+	o, e := b.replay(traceRead, addr, reg, data)
+	if e != nil {
+		return 0, e
+	}
+	return copy(data, o.data), nil
+}
+
+// WriteSliceToReg verifies that the data matches the trace.
+func (b *ReplayBus) WriteSliceToReg(addr, reg byte, data []byte) (int, error) {
+	_, e := b.replay(traceWrite, addr, reg, data)
+	if e != nil {
+		return 0, e
+	}
+	return len(data), nil
+}
@@ -0,0 +1,56 @@
+package minimu9
+
+import "time"
+
+// retryingBus is a Bus which retries failed operations.
+type retryingBus struct {
+	bus     Bus
+	retries int
+	backoff time.Duration
+}
+
+// NewRetryingBus wraps bus to retry failed operations up to the specified number of times,
+// sleeping for backoff before each retry. This helps with transient errors on long or noisy
+// buses. The error of the last attempt is returned if all of them fail.
+func NewRetryingBus(bus Bus, retries int, backoff time.Duration) Bus {
+	return &retryingBus{bus: bus, retries: retries, backoff: backoff}
+}
+
+func (b *retryingBus) retry(f func() error) (e error) {
+	for attempt := 0; ; attempt++ {
+		if e = f(); e == nil || attempt >= b.retries {
+			return
+		}
+		time.Sleep(b.backoff)
+	}
+}
+
+func (b *retryingBus) ReadByteFromReg(addr, reg byte) (value byte, e error) {
+	e = b.retry(func() (e error) {
+		value, e = b.bus.ReadByteFromReg(addr, reg)
+		return
+	})
+	return
+}
+
+func (b *retryingBus) ReadSliceFromReg(addr, reg byte, data []byte) (n int, e error) {
+	e = b.retry(func() (e error) {
+		n, e = b.bus.ReadSliceFromReg(addr, reg, data)
+		return
+	})
+	return
+}
+
+func (b *retryingBus) WriteByteToReg(addr, reg, value byte) error {
+	return b.retry(func() error {
+		return b.bus.WriteByteToReg(addr, reg, value)
+	})
+}
+
+func (b *retryingBus) WriteSliceToReg(addr, reg byte, data []byte) (n int, e error) {
+	e = b.retry(func() (e error) {
+		n, e = b.bus.WriteSliceToReg(addr, reg, data)
+		return
+	})
+	return
+}
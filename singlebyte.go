@@ -0,0 +1,50 @@
+package minimu9
+
+// singleByteBus is a Bus which emulates slice transfers with single-byte ones.
+type singleByteBus struct {
+	bus Bus
+}
+
+// NewSingleByteBus wraps bus to transfer each byte of ReadSliceFromReg() and WriteSliceToReg()
+// as a separate single-byte operation, for buses or multiplexers which don't support burst
+// transfers. The register is advanced on every byte if its address has MSB set, otherwise the
+// same register is accessed repeatedly; MSB is cleared in the single-byte operations.
+func NewSingleByteBus(bus Bus) Bus {
+	return &singleByteBus{bus: bus}
+}
+
+// advance returns i-th register accessed by a slice transfer starting at reg.
+func advance(reg byte, i int) byte {
+	if reg&(1<<7) == 0 {
+		return reg
+	}
+	return (reg &^ (1 << 7)) + byte(i)
+}
+
+func (b *singleByteBus) ReadByteFromReg(addr, reg byte) (byte, error) {
+	return b.bus.ReadByteFromReg(addr, reg)
+}
+
+func (b *singleByteBus) ReadSliceFromReg(addr, reg byte, data []byte) (int, error) {
+	for i := range data {
+		value, e := b.bus.ReadByteFromReg(addr, advance(reg, i))
+		if e != nil {
+			return i, e
+		}
+		data[i] = value
+	}
+	return len(data), nil
+}
+
+func (b *singleByteBus) WriteByteToReg(addr, reg, value byte) error {
+	return b.bus.WriteByteToReg(addr, reg, value)
+}
+
+func (b *singleByteBus) WriteSliceToReg(addr, reg byte, data []byte) (int, error) {
+	for i, value := range data {
+		if e := b.bus.WriteByteToReg(addr, advance(reg, i), value); e != nil {
+			return i, e
+		}
+	}
+	return len(data), nil
+}
@@ -0,0 +1,63 @@
+package minimu9
+
+// SPIDevice is a full-duplex SPI connection to a single slave (e.g. spidev), with chip select
+// asserted for the duration of each transfer.
+type SPIDevice interface {
+	// Tx writes w to the slave, simultaneously reading len(r) (same as len(w)) bytes into r.
+	Tx(w, r []byte) error
+}
+
+// spiBus is a Bus over SPI.
+type spiBus struct {
+	dev SPIDevice
+}
+
+// NewSPIBus creates a Bus talking to a sensor over SPI. The slave address is ignored, and the
+// register auto-increment bit (MSB of the address) is translated into SPI convention, where the
+// first byte is R/W bit, auto-increment bit and a 6-bit register address.
+func NewSPIBus(dev SPIDevice) Bus {
+	return &spiBus{dev: dev}
+}
+
+// spiCommand returns the first byte of an SPI transfer.
+func spiCommand(reg byte, read bool) byte {
+	command := reg & 0x3f
+	if reg&(1<<7) != 0 {
+		command |= 1 << 6
+	}
+	if read {
+		command |= 1 << 7
+	}
+	return command
+}
+
+func (b *spiBus) ReadByteFromReg(addr, reg byte) (byte, error) {
+	data := make([]byte, 1)
+	_, e := b.ReadSliceFromReg(addr, reg&^(1<<7), data)
+	return data[0], e
+}
+
+func (b *spiBus) ReadSliceFromReg(addr, reg byte, data []byte) (int, error) {
+	w := make([]byte, len(data)+1)
+	r := make([]byte, len(w))
+	w[0] = spiCommand(reg, true)
+	if e := b.dev.Tx(w, r); e != nil {
+		return 0, e
+	}
+	return copy(data, r[1:]), nil
+}
+
+func (b *spiBus) WriteByteToReg(addr, reg, value byte) error {
+	_, e := b.WriteSliceToReg(addr, reg&^(1<<7), []byte{value})
+	return e
+}
+
+func (b *spiBus) WriteSliceToReg(addr, reg byte, data []byte) (int, error) {
+	w := make([]byte, len(data)+1)
+	w[0] = spiCommand(reg, false)
+	copy(w[1:], data)
+	if e := b.dev.Tx(w, make([]byte, len(w))); e != nil {
+		return 0, e
+	}
+	return len(data), nil
+}
@@ -6,7 +6,6 @@ import (
 	"math"
 	"time"
 
-	"github.com/dasfoo/i2c"
 	"github.com/golang/geo/r3"
 )
 
@@ -25,39 +24,62 @@ func (v *IntVector) R3() r3.Vector {
 	}
 }
 
-// ReadStatusAndVector reads status byte, and 3x2-byte X, Y and Z int16 vector values.
-func ReadStatusAndVector(bus i2c.Bus, addr, reg byte) (
-	v r3.Vector, e error) {
-	var status byte
-	if status, e = bus.ReadByteFromReg(addr, reg); e != nil {
-		return
+// ReadStatusAndVector reads status byte, and 3x2-byte X, Y and Z int16 little-endian vector values.
+func ReadStatusAndVector(bus Bus, addr, reg byte) (r3.Vector, error) {
+	return ReadStatusAndVectorWithByteOrder(bus, addr, reg, binary.LittleEndian)
+}
+
+// ReadStatusAndVectorWithByteOrder is the same as ReadStatusAndVector for the specified byte order.
+func ReadStatusAndVectorWithByteOrder(bus Bus, addr, reg byte, order binary.ByteOrder) (
+	r3.Vector, error) {
+	status, v, e := ReadStatusByteAndVector(bus, addr, reg, order)
+	if e != nil {
+		return r3.Vector{}, e
 	}
-	var iv IntVector
-	if iv, e = ReadVector(bus, addr, reg+1); e != nil {
+	return v.R3(), StatusError(status)
+}
+
+// ReadStatusByteAndVector reads status byte, and X, Y and Z IntVector values in the specified
+// byte order, without interpreting the status.
+func ReadStatusByteAndVector(bus Bus, addr, reg byte, order binary.ByteOrder) (
+	status byte, v IntVector, e error) {
+	if status, e = bus.ReadByteFromReg(addr, reg); e != nil {
 		return
 	}
-	if status&0xf0 > 0 {
-		e = &DataAvailabilityError{NewDataWasOverwritten: true}
-	} else if status&0x0f == 0 {
-		e = &DataAvailabilityError{NewDataNotAvailable: true}
-	}
-	v = iv.R3()
+	v, e = ReadVectorWithByteOrder(bus, addr, reg+1, order)
 	return
 }
 
-// ReadVector reads IntVector dimensions.
-func ReadVector(bus i2c.Bus, addr, reg byte) (v IntVector, e error) {
+// ReadVector reads little-endian IntVector dimensions.
+func ReadVector(bus Bus, addr, reg byte) (IntVector, error) {
+	return ReadVectorWithByteOrder(bus, addr, reg, binary.LittleEndian)
+}
+
+// ReadVectorWithByteOrder reads IntVector dimensions in the specified byte order.
+func ReadVectorWithByteOrder(bus Bus, addr, reg byte, order binary.ByteOrder) (
+	v IntVector, e error) {
 	data := make([]byte, 6)
 	// Set MSB for the slave to advance the register on every read.
-	if _, e = bus.ReadSliceFromReg(addr, reg|(1<<7), data); e != nil {
+	if e = ReadFullSliceFromReg(bus, addr, reg|(1<<7), data); e != nil {
 		return
 	}
-	e = binary.Read(bytes.NewReader(data), binary.LittleEndian, &v)
-	return
+	return DecodeVector(data, order), nil
+}
+
+// DecodeVector decodes X, Y and Z IntVector values from the first 6 bytes of data in the specified
+// byte order. Unlike binary.Read, it does not allocate. Bytes are assembled as unsigned before
+// converting to int16, i.e. int16(uint16(high)<<8 | uint16(low)), so that a low byte with MSB set
+// is not sign-extended into the high byte.
+func DecodeVector(data []byte, order binary.ByteOrder) IntVector {
+	return IntVector{
+		X: int16(order.Uint16(data[0:])),
+		Y: int16(order.Uint16(data[2:])),
+		Z: int16(order.Uint16(data[4:])),
+	}
 }
 
 // WriteVector writes IntVector dimensions.
-func WriteVector(bus i2c.Bus, addr, reg byte, v IntVector) error {
+func WriteVector(bus Bus, addr, reg byte, v IntVector) error {
 	var data bytes.Buffer
 	if e := binary.Write(&data, binary.LittleEndian, &v); e != nil {
 		return e
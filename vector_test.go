@@ -0,0 +1,48 @@
+package minimu9
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestDecodeVector(t *testing.T) {
+	for _, test := range []struct {
+		high, low byte
+		expected  int16
+	}{
+		{0x00, 0x00, 0},
+		{0x7f, 0xff, 32767},
+		{0x80, 0x00, -32768},
+		{0xff, 0xff, -1},
+		{0x00, 0x80, 128},
+		{0x00, 0xff, 255},
+		{0x12, 0x80, 0x1280},
+		{0xff, 0x80, -128},
+		{0x80, 0xff, -32513},
+	} {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			first, second := test.low, test.high
+			if order == binary.BigEndian {
+				first, second = test.high, test.low
+			}
+			// Put the value into each axis in turn, with other axes set to values which would be
+			// visible if bytes were mixed up between axes.
+			for axis := 0; axis < 3; axis++ {
+				data := []byte{0x01, 0x01, 0x01, 0x01, 0x01, 0x01}
+				data[axis*2], data[axis*2+1] = first, second
+				v := DecodeVector(data, order)
+				components := []int16{v.X, v.Y, v.Z}
+				for i, component := range components {
+					expected := int16(0x0101)
+					if i == axis {
+						expected = test.expected
+					}
+					if component != expected {
+						t.Errorf("%v, high 0x%02X, low 0x%02X on axis %d: expected %d on axis %d, "+
+							"got %d", order, test.high, test.low, axis, expected, i, component)
+					}
+				}
+			}
+		}
+	}
+}